@@ -16,10 +16,28 @@ args+=("--github-action-output=true")
 [ -n "$INPUT_THRESHOLD_FILE" ] && [ "$INPUT_THRESHOLD_FILE" != "-1" ] && args+=("--threshold-file=$INPUT_THRESHOLD_FILE")
 [ -n "$INPUT_THRESHOLD_PACKAGE" ] && [ "$INPUT_THRESHOLD_PACKAGE" != "-1" ] && args+=("--threshold-package=$INPUT_THRESHOLD_PACKAGE")
 [ -n "$INPUT_THRESHOLD_TOTAL" ] && [ "$INPUT_THRESHOLD_TOTAL" != "-1" ] && args+=("--threshold-total=$INPUT_THRESHOLD_TOTAL")
+[ -n "$INPUT_THRESHOLD_NEW_CODE" ] && [ "$INPUT_THRESHOLD_NEW_CODE" != "-1" ] && args+=("--threshold-new-code=$INPUT_THRESHOLD_NEW_CODE")
+[ -n "$INPUT_THRESHOLD_FUNCTION" ] && [ "$INPUT_THRESHOLD_FUNCTION" != "-1" ] && args+=("--threshold-function=$INPUT_THRESHOLD_FUNCTION")
+[ -n "$INPUT_COLD_BLOCK_HITS" ] && [ "$INPUT_COLD_BLOCK_HITS" != "-1" ] && args+=("--cold-block-hits=$INPUT_COLD_BLOCK_HITS")
 
 # Badge and CDN/Git configs (only if specified)
 [ -n "$INPUT_BREAKDOWN_FILE_NAME" ] && args+=("--breakdown-file-name=$INPUT_BREAKDOWN_FILE_NAME")
 [ -n "$INPUT_DIFF_BASE_BREAKDOWN_FILE_NAME" ] && args+=("--diff-base-breakdown-file-name=$INPUT_DIFF_BASE_BREAKDOWN_FILE_NAME")
+[ -n "$INPUT_DIFF_PATCH_FILE_NAME" ] && args+=("--diff-patch-file-name=$INPUT_DIFF_PATCH_FILE_NAME")
+if [ -n "$INPUT_DIFF_GIT_BASE" ]; then
+  args+=("--diff-git-base=$INPUT_DIFF_GIT_BASE")
+  # workspace is mounted with different owner, which git refuses without safe.directory
+  git config --global --add safe.directory "${GITHUB_WORKSPACE:-/github/workspace}"
+fi
+[ -n "$INPUT_RATCHET_FILE_NAME" ] && args+=("--ratchet-file-name=$INPUT_RATCHET_FILE_NAME")
+[ "$INPUT_UPDATE_RATCHET" = "true" ] && args+=("--update-ratchet=true")
+[ -n "$INPUT_HTML_REPORT" ] && args+=("--html-report=$INPUT_HTML_REPORT")
+[ -n "$INPUT_COBERTURA_FILE" ] && args+=("--cobertura-file=$INPUT_COBERTURA_FILE")
+[ -n "$INPUT_LCOV_FILE" ] && args+=("--lcov-file=$INPUT_LCOV_FILE")
+[ -n "$INPUT_JSON_REPORT" ] && args+=("--json-report=$INPUT_JSON_REPORT")
+[ -n "$INPUT_SARIF_FILE" ] && args+=("--sarif-file=$INPUT_SARIF_FILE")
+[ "$INPUT_SARIF_UNCOVERED_LINES" = "true" ] && args+=("--sarif-uncovered-lines=true")
+[ -n "$INPUT_JUNIT_FILE" ] && args+=("--junit-file=$INPUT_JUNIT_FILE")
 [ -n "$INPUT_BADGE_FILE_NAME" ] && args+=("--badge-file-name=$INPUT_BADGE_FILE_NAME")
 
 # CDN options
@@ -36,6 +54,15 @@ args+=("--github-action-output=true")
 [ -n "$INPUT_GIT_BRANCH" ] && args+=("--git-branch=$INPUT_GIT_BRANCH")
 [ -n "$INPUT_GIT_REPOSITORY" ] && args+=("--git-repository=$INPUT_GIT_REPOSITORY")
 [ -n "$INPUT_GIT_FILE_NAME" ] && args+=("--git-file-name=$INPUT_GIT_FILE_NAME")
+[ -n "$INPUT_PR_COMMENT_TOKEN" ] && args+=("--pr-comment-token=$INPUT_PR_COMMENT_TOKEN")
+[ -n "$INPUT_PR_COMMENT_REPOSITORY" ] && args+=("--pr-comment-repository=$INPUT_PR_COMMENT_REPOSITORY")
+[ -n "$INPUT_PR_COMMENT_NUMBER" ] && args+=("--pr-comment-number=$INPUT_PR_COMMENT_NUMBER")
+[ -n "$INPUT_PR_COMMENT_BASE_URL" ] && args+=("--pr-comment-base-url=$INPUT_PR_COMMENT_BASE_URL")
+[ -n "$INPUT_CHECK_RUN_TOKEN" ] && args+=("--check-run-token=$INPUT_CHECK_RUN_TOKEN")
+[ -n "$INPUT_CHECK_RUN_REPOSITORY" ] && args+=("--check-run-repository=$INPUT_CHECK_RUN_REPOSITORY")
+[ -n "$INPUT_CHECK_RUN_HEAD_SHA" ] && args+=("--check-run-head-sha=$INPUT_CHECK_RUN_HEAD_SHA")
+[ -n "$INPUT_CHECK_RUN_NAME" ] && args+=("--check-run-name=$INPUT_CHECK_RUN_NAME")
+[ -n "$INPUT_CHECK_RUN_BASE_URL" ] && args+=("--check-run-base-url=$INPUT_CHECK_RUN_BASE_URL")
 
 # Execute the command
 exec "${args[@]}"
\ No newline at end of file
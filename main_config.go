@@ -11,17 +11,37 @@ import (
 )
 
 type args struct {
-	ConfigPath         *string `arg:"-c,--config"`
-	Profile            *string `arg:"-p,--profile"              help:"path to coverage profile"`
-	Debug              *bool   `arg:"-d,--debug"`
-	SourceDir          *string `arg:"-s,--source-dir"`
-	GithubActionOutput *bool   `arg:"-o,--github-action-output"`
-	ThresholdFile      *int    `arg:"-f,--threshold-file"`
-	ThresholdPackage   *int    `arg:"-k,--threshold-package"`
-	ThresholdTotal     *int    `arg:"-t,--threshold-total"`
+	ConfigPath *string `arg:"-c,--config"`
+	Profile    *string `arg:"-p,--profile" help:"path to coverage profile or coverage data dir"`
+
+	Debug              *bool    `arg:"-d,--debug"`
+	SourceDir          *string  `arg:"-s,--source-dir"`
+	GithubActionOutput *bool    `arg:"-o,--github-action-output"`
+	GitlabOutput       *bool    `arg:"--gitlab-output"`
+	ThresholdFile      *float64 `arg:"-f,--threshold-file"`
+	ThresholdPackage   *float64 `arg:"-k,--threshold-package"`
+	ThresholdTotal     *float64 `arg:"-t,--threshold-total"`
+	ThresholdNewCode   *float64 `arg:"--threshold-new-code"`
+	ThresholdFunction  *float64 `arg:"--threshold-function"`
+	ColdBlockHits      *int     `arg:"--cold-block-hits"`
 
 	BreakdownFileName         *string `arg:"--breakdown-file-name"`
 	DiffBaseBreakdownFileName *string `arg:"--diff-base-breakdown-file-name"`
+	DiffPatchFileName         *string `arg:"--diff-patch-file-name"`
+	DiffGitBase               *string `arg:"--diff-git-base"`
+
+	RatchetFileName  *string  `arg:"--ratchet-file-name"`
+	RatchetTolerance *float64 `arg:"--ratchet-tolerance"`
+	UpdateRatchet    *bool    `arg:"--update-ratchet"    help:"raise values stored in ratchet file"`
+
+	HTMLReport    *string `arg:"--html-report"    help:"path to html report file"`
+	CoberturaFile *string `arg:"--cobertura-file" help:"path to cobertura xml report file"`
+	LcovFile      *string `arg:"--lcov-file"      help:"path to lcov tracefile"`
+	JSONReport    *string `arg:"--json-report"    help:"path to json report file"`
+	SarifFile     *string `arg:"--sarif-file"     help:"path to sarif report file"`
+	JUnitFile     *string `arg:"--junit-file"     help:"path to junit xml report file"`
+
+	SarifUncoveredLines *bool `arg:"--sarif-uncovered-lines" help:"include uncovered lines in sarif"`
 
 	BadgeFileName *string `arg:"-b,--badge-file-name"`
 
@@ -37,6 +57,17 @@ type args struct {
 	GitRepository *string `arg:"--git-repository"`
 	GitBranch     *string `arg:"--git-branch"`
 	GitFileName   *string `arg:"--git-file-name"`
+
+	PRCommentToken      *string `arg:"--pr-comment-token"`
+	PRCommentRepository *string `arg:"--pr-comment-repository"`
+	PRCommentNumber     *int    `arg:"--pr-comment-number"`
+	PRCommentBaseURL    *string `arg:"--pr-comment-base-url"`
+
+	CheckRunToken      *string `arg:"--check-run-token"`
+	CheckRunRepository *string `arg:"--check-run-repository"`
+	CheckRunHeadSHA    *string `arg:"--check-run-head-sha"`
+	CheckRunName       *string `arg:"--check-run-name"`
+	CheckRunBaseURL    *string `arg:"--check-run-base-url"`
 }
 
 func (*args) Version() string {
@@ -48,12 +79,30 @@ func (a *args) overrideConfig(cfg testcoverage.Config) (testcoverage.Config, err
 	setValue(&cfg.Debug, a.Debug)
 	setValue(&cfg.SourceDir, a.SourceDir)
 	setValue(&cfg.GithubActionOutput, a.GithubActionOutput)
+	setValue(&cfg.GitlabOutput, a.GitlabOutput)
 	setValue(&cfg.Threshold.File, a.ThresholdFile)
 	setValue(&cfg.Threshold.Package, a.ThresholdPackage)
 	setValue(&cfg.Threshold.Total, a.ThresholdTotal)
+	setValue(&cfg.Threshold.NewCode, a.ThresholdNewCode)
+	setValue(&cfg.Threshold.Function, a.ThresholdFunction)
+	setValue(&cfg.ColdBlockHits, a.ColdBlockHits)
 
 	setValue(&cfg.BreakdownFileName, a.BreakdownFileName)
 	setValue(&cfg.Diff.BaseBreakdownFileName, a.DiffBaseBreakdownFileName)
+	setValue(&cfg.Diff.PatchFileName, a.DiffPatchFileName)
+	setValue(&cfg.Diff.GitBase, a.DiffGitBase)
+
+	setValue(&cfg.Ratchet.FileName, a.RatchetFileName)
+	setValue(&cfg.Ratchet.Tolerance, a.RatchetTolerance)
+	setValue(&cfg.Ratchet.Update, a.UpdateRatchet)
+
+	setValue(&cfg.HTMLReport, a.HTMLReport)
+	setValue(&cfg.CoberturaFile, a.CoberturaFile)
+	setValue(&cfg.LcovFile, a.LcovFile)
+	setValue(&cfg.JSONReport, a.JSONReport)
+	setValue(&cfg.SarifFile, a.SarifFile)
+	setValue(&cfg.SarifUncoveredLines, a.SarifUncoveredLines)
+	setValue(&cfg.JUnitFile, a.JUnitFile)
 
 	setValue(&cfg.Badge.FileName, a.BadgeFileName)
 
@@ -83,6 +132,39 @@ func (a *args) overrideConfig(cfg testcoverage.Config) (testcoverage.Config, err
 		}
 	}
 
+	if a.PRCommentToken != nil {
+		setValue(&cfg.PRComment.Token, a.PRCommentToken)
+		setValue(&cfg.PRComment.PullRequest, a.PRCommentNumber)
+		setValue(&cfg.PRComment.BaseURL, a.PRCommentBaseURL)
+
+		if a.PRCommentRepository != nil {
+			parts := strings.Split(*a.PRCommentRepository, "/")
+			if len(parts) != 2 { //nolint:mnd // relax
+				return cfg, errors.New("--pr-comment-repository flag should have format {owner}/{repository}")
+			}
+
+			cfg.PRComment.Owner = parts[0]
+			cfg.PRComment.Repository = parts[1]
+		}
+	}
+
+	if a.CheckRunToken != nil {
+		setValue(&cfg.CheckRun.Token, a.CheckRunToken)
+		setValue(&cfg.CheckRun.HeadSHA, a.CheckRunHeadSHA)
+		setValue(&cfg.CheckRun.Name, a.CheckRunName)
+		setValue(&cfg.CheckRun.BaseURL, a.CheckRunBaseURL)
+
+		if a.CheckRunRepository != nil {
+			parts := strings.Split(*a.CheckRunRepository, "/")
+			if len(parts) != 2 { //nolint:mnd // relax
+				return cfg, errors.New("--check-run-repository flag should have format {owner}/{repository}")
+			}
+
+			cfg.CheckRun.Owner = parts[0]
+			cfg.CheckRun.Repository = parts[1]
+		}
+	}
+
 	return cfg, nil
 }
 
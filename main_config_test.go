@@ -8,6 +8,8 @@ import (
 
 	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage"
 	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/badgestorer"
+	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/checkrun"
+	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/prcomment"
 )
 
 func ptr[T any](v T) *T { return &v }
@@ -49,6 +51,14 @@ func Test_args_overrideConfig(t *testing.T) {
 		assert.True(t, result.GithubActionOutput)
 	})
 
+	t.Run("GitlabOutput", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := (&args{GitlabOutput: ptr(true)}).overrideConfig(testcoverage.Config{})
+		assert.NoError(t, err)
+		assert.True(t, result.GitlabOutput)
+	})
+
 	t.Run("SourceDir", func(t *testing.T) {
 		t.Parallel()
 
@@ -60,25 +70,49 @@ func Test_args_overrideConfig(t *testing.T) {
 	t.Run("ThresholdFile", func(t *testing.T) {
 		t.Parallel()
 
-		result, err := (&args{ThresholdFile: ptr(80)}).overrideConfig(testcoverage.Config{})
+		result, err := (&args{ThresholdFile: ptr(80.0)}).overrideConfig(testcoverage.Config{})
 		assert.NoError(t, err)
-		assert.Equal(t, 80, result.Threshold.File)
+		assert.InDelta(t, 80.0, result.Threshold.File, 0)
 	})
 
 	t.Run("ThresholdPackage", func(t *testing.T) {
 		t.Parallel()
 
-		result, err := (&args{ThresholdPackage: ptr(70)}).overrideConfig(testcoverage.Config{})
+		result, err := (&args{ThresholdPackage: ptr(70.0)}).overrideConfig(testcoverage.Config{})
 		assert.NoError(t, err)
-		assert.Equal(t, 70, result.Threshold.Package)
+		assert.InDelta(t, 70.0, result.Threshold.Package, 0)
 	})
 
 	t.Run("ThresholdTotal", func(t *testing.T) {
 		t.Parallel()
 
-		result, err := (&args{ThresholdTotal: ptr(90)}).overrideConfig(testcoverage.Config{})
+		result, err := (&args{ThresholdTotal: ptr(87.5)}).overrideConfig(testcoverage.Config{})
+		assert.NoError(t, err)
+		assert.InDelta(t, 87.5, result.Threshold.Total, 0)
+	})
+
+	t.Run("ThresholdNewCode", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := (&args{ThresholdNewCode: ptr(85.0)}).overrideConfig(testcoverage.Config{})
+		assert.NoError(t, err)
+		assert.InDelta(t, 85.0, result.Threshold.NewCode, 0)
+	})
+
+	t.Run("ThresholdFunction", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := (&args{ThresholdFunction: ptr(60.0)}).overrideConfig(testcoverage.Config{})
 		assert.NoError(t, err)
-		assert.Equal(t, 90, result.Threshold.Total)
+		assert.InDelta(t, 60.0, result.Threshold.Function, 0)
+	})
+
+	t.Run("ColdBlockHits", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := (&args{ColdBlockHits: ptr(5)}).overrideConfig(testcoverage.Config{})
+		assert.NoError(t, err)
+		assert.Equal(t, 5, result.ColdBlockHits)
 	})
 
 	t.Run("BreakdownFileName", func(t *testing.T) {
@@ -97,6 +131,88 @@ func Test_args_overrideConfig(t *testing.T) {
 		assert.Equal(t, "base.out", result.Diff.BaseBreakdownFileName)
 	})
 
+	t.Run("DiffPatchFileName", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := (&args{DiffPatchFileName: ptr("changes.patch")}).overrideConfig(testcoverage.Config{})
+		assert.NoError(t, err)
+		assert.Equal(t, "changes.patch", result.Diff.PatchFileName)
+	})
+
+	t.Run("DiffGitBase", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := (&args{DiffGitBase: ptr("origin/main")}).overrideConfig(testcoverage.Config{})
+		assert.NoError(t, err)
+		assert.Equal(t, "origin/main", result.Diff.GitBase)
+	})
+
+	t.Run("Ratchet", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := (&args{
+			RatchetFileName:  ptr("ratchet.testcoverage"),
+			RatchetTolerance: ptr(0.5),
+			UpdateRatchet:    ptr(true),
+		}).overrideConfig(testcoverage.Config{})
+		assert.NoError(t, err)
+		assert.Equal(t, "ratchet.testcoverage", result.Ratchet.FileName)
+		assert.InDelta(t, 0.5, result.Ratchet.Tolerance, 0)
+		assert.True(t, result.Ratchet.Update)
+	})
+
+	t.Run("HTMLReport", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := (&args{HTMLReport: ptr("coverage.html")}).overrideConfig(testcoverage.Config{})
+		assert.NoError(t, err)
+		assert.Equal(t, "coverage.html", result.HTMLReport)
+	})
+
+	t.Run("CoberturaFile", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := (&args{CoberturaFile: ptr("cobertura.xml")}).overrideConfig(testcoverage.Config{})
+		assert.NoError(t, err)
+		assert.Equal(t, "cobertura.xml", result.CoberturaFile)
+	})
+
+	t.Run("LcovFile", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := (&args{LcovFile: ptr("lcov.info")}).overrideConfig(testcoverage.Config{})
+		assert.NoError(t, err)
+		assert.Equal(t, "lcov.info", result.LcovFile)
+	})
+
+	t.Run("JSONReport", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := (&args{JSONReport: ptr("report.json")}).overrideConfig(testcoverage.Config{})
+		assert.NoError(t, err)
+		assert.Equal(t, "report.json", result.JSONReport)
+	})
+
+	t.Run("SarifFile", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := (&args{
+			SarifFile:           ptr("report.sarif"),
+			SarifUncoveredLines: ptr(true),
+		}).overrideConfig(testcoverage.Config{})
+		assert.NoError(t, err)
+		assert.Equal(t, "report.sarif", result.SarifFile)
+		assert.True(t, result.SarifUncoveredLines)
+	})
+
+	t.Run("JUnitFile", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := (&args{JUnitFile: ptr("junit.xml")}).overrideConfig(testcoverage.Config{})
+		assert.NoError(t, err)
+		assert.Equal(t, "junit.xml", result.JUnitFile)
+	})
+
 	t.Run("BadgeFileName", func(t *testing.T) {
 		t.Parallel()
 
@@ -208,6 +324,88 @@ func Test_args_overrideConfig(t *testing.T) {
 		assert.Empty(t, result.Badge.Git.Owner)
 	})
 
+	t.Run("PR comment token with valid repository", func(t *testing.T) {
+		t.Parallel()
+
+		a := &args{
+			PRCommentToken:      ptr("token"),
+			PRCommentRepository: ptr("owner/repo"),
+			PRCommentNumber:     ptr(42),
+			PRCommentBaseURL:    ptr("https://github.example.com/api/v3/"),
+		}
+		result, err := a.overrideConfig(testcoverage.Config{})
+		assert.NoError(t, err)
+		assert.Equal(t, prcomment.Github{
+			Token:       "token",
+			Owner:       "owner",
+			Repository:  "repo",
+			PullRequest: 42,
+			BaseURL:     "https://github.example.com/api/v3/",
+		}, result.PRComment)
+	})
+
+	t.Run("PR comment token with invalid repository format", func(t *testing.T) {
+		t.Parallel()
+
+		a := &args{
+			PRCommentToken:      ptr("token"),
+			PRCommentRepository: ptr("invalid-no-slash"),
+		}
+		_, err := a.overrideConfig(testcoverage.Config{})
+		assert.Error(t, err)
+	})
+
+	t.Run("PR comment not set when token is nil", func(t *testing.T) {
+		t.Parallel()
+
+		a := &args{PRCommentRepository: ptr("owner/repo"), PRCommentNumber: ptr(42)}
+		result, err := a.overrideConfig(testcoverage.Config{})
+		assert.NoError(t, err)
+		assert.Empty(t, result.PRComment)
+	})
+
+	t.Run("check run token with valid repository", func(t *testing.T) {
+		t.Parallel()
+
+		a := &args{
+			CheckRunToken:      ptr("token"),
+			CheckRunRepository: ptr("owner/repo"),
+			CheckRunHeadSHA:    ptr("abc"),
+			CheckRunName:       ptr("coverage"),
+			CheckRunBaseURL:    ptr("https://github.example.com/api/v3/"),
+		}
+		result, err := a.overrideConfig(testcoverage.Config{})
+		assert.NoError(t, err)
+		assert.Equal(t, checkrun.Github{
+			Token:      "token",
+			Owner:      "owner",
+			Repository: "repo",
+			HeadSHA:    "abc",
+			Name:       "coverage",
+			BaseURL:    "https://github.example.com/api/v3/",
+		}, result.CheckRun)
+	})
+
+	t.Run("check run token with invalid repository format", func(t *testing.T) {
+		t.Parallel()
+
+		a := &args{
+			CheckRunToken:      ptr("token"),
+			CheckRunRepository: ptr("invalid-no-slash"),
+		}
+		_, err := a.overrideConfig(testcoverage.Config{})
+		assert.Error(t, err)
+	})
+
+	t.Run("check run not set when token is nil", func(t *testing.T) {
+		t.Parallel()
+
+		a := &args{CheckRunRepository: ptr("owner/repo"), CheckRunHeadSHA: ptr("abc")}
+		result, err := a.overrideConfig(testcoverage.Config{})
+		assert.NoError(t, err)
+		assert.Empty(t, result.CheckRun)
+	})
+
 	t.Run("args do not override existing config values when nil", func(t *testing.T) {
 		t.Parallel()
 
@@ -6,10 +6,14 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
+	"runtime/debug"
 	"strings"
+	"time"
 
 	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/coverage"
 	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/logger"
+	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/patch"
 )
 
 //nolint:maintidx // relax
@@ -35,7 +39,7 @@ func Check(wout io.Writer, cfg Config) (bool, error) {
 	logger.L.Info().Msg("running check...")
 	logger.L.Info().Any("config", cfg.Redacted()).Msg("using configuration")
 
-	currentStats, err := GenerateCoverageStats(cfg)
+	currentStats, exclusions, err := generateCoverage(cfg)
 	if err != nil {
 		return handleErr(err, "failed to generate coverage statistics")
 	}
@@ -50,20 +54,39 @@ func Check(wout io.Writer, cfg Config) (bool, error) {
 		return handleErr(err, "failed to load base coverage breakdown")
 	}
 
-	result := Analyze(cfg, currentStats, baseStats)
+	ratchet, err := loadRatchet(cfg)
+	if err != nil {
+		return handleErr(err, "failed to load ratchet")
+	}
 
-	report := reportForHuman(w, result)
+	changedLines, err := LoadChangedLines(cfg)
+	if err != nil {
+		return handleErr(err, "failed to load changed lines")
+	}
 
-	if cfg.GithubActionOutput {
-		ReportForGithubAction(w, result)
+	result := AnalyzeWithChangedLines(cfg, currentStats, baseStats, changedLines)
+	result.FilesExcludedBySource = exclusions
 
-		err = SetGithubActionOutput(result, report)
+	if ratchet != nil {
+		result.HasRatchet = true
+		result.RatchetRegressions = checkRatchet(*ratchet, result, cfg.Ratchet.Tolerance)
+	}
+
+	for _, r := range reportsFromConfig(cfg) {
+		err = makeReport(w, cfg, r, result)
 		if err != nil {
-			return handleErr(err, "failed setting github action output")
+			return handleErr(err, reportErrorMsg(r.Type))
 		}
 	}
 
-	err = generateAndSaveBadge(w, cfg, result.TotalStats.CoveredPercentage())
+	if ratchet != nil && cfg.Ratchet.Update {
+		err = saveRatchet(cfg, raiseRatchet(newBreakdownHeader(cfg), *ratchet, result))
+		if err != nil {
+			return handleErr(err, "failed to save ratchet")
+		}
+	}
+
+	err = generateAndSaveBadge(w, cfg, badgeCoverage(result))
 	if err != nil {
 		return handleErr(err, "failed to generate and save badge")
 	}
@@ -71,68 +94,127 @@ func Check(wout io.Writer, cfg Config) (bool, error) {
 	return result.Pass(), nil
 }
 
-func reportForHuman(w io.Writer, result AnalyzeResult) string {
-	buffer := &bytes.Buffer{}
-	out := bufio.NewWriter(buffer)
-
-	ReportForHuman(out, result)
-	out.Flush()
-
-	w.Write(buffer.Bytes()) //nolint:errcheck // relax
-
-	return buffer.String()
+func GenerateCoverageStats(cfg Config) ([]coverage.Stats, error) {
+	stats, _, err := generateCoverage(cfg)
+	return stats, err
 }
 
-func GenerateCoverageStats(cfg Config) ([]coverage.Stats, error) {
-	return coverage.GenerateCoverageStats(coverage.Config{ //nolint:wrapcheck // err wrapped above
+func generateCoverage(cfg Config) ([]coverage.Stats, []coverage.Exclusion, error) {
+	return coverage.GenerateCoverage(coverage.Config{ //nolint:wrapcheck // err wrapped above
 		Profiles:               strings.Split(cfg.Profile, ","),
 		ExcludePaths:           cfg.Exclude.Paths,
 		SourceDir:              cfg.SourceDir,
 		ForceAnnotationComment: cfg.ForceAnnotationComment,
+		ForceAnnotationIssue:   cfg.ForceAnnotationIssue,
+		ExcludeGenerated:       cfg.Exclude.Generated,
 	})
 }
 
 func Analyze(cfg Config, current, base []coverage.Stats) AnalyzeResult {
 	thr := cfg.Threshold
+	mu := cfg.MaxUncovered
+	mul := cfg.MaxUncoveredLines
 	overrideRules := compileOverridePathRules(cfg)
-	hasFileOverrides, hasPackageOverrides := detectOverrides(cfg.Override)
+	hasFileOverrides, hasPackageOverrides, hasFunctionOverrides := detectOverrides(cfg.Override)
 
 	var filesWithMissingExplanations []coverage.Stats
 	if cfg.ForceAnnotationComment {
 		filesWithMissingExplanations = coverage.StatsFilterWithMissingExplanations(current)
 	}
 
+	var filesWithMissingIssues []coverage.Stats
+	if cfg.ForceAnnotationIssue {
+		filesWithMissingIssues = coverage.StatsFilterWithMissingIssues(current)
+	}
+
+	packages := makePackageStats(current)
+	coverage.SortStatsByName(packages)
+
+	functions := coverage.StatsFunctions(current)
+	funcOverrideRules := compileOverrideFunctionRules(cfg)
+
 	return AnalyzeResult{
-		Threshold:           thr,
-		DiffThreshold:       cfg.Diff.Threshold,
-		HasFileOverrides:    hasFileOverrides,
-		HasPackageOverrides: hasPackageOverrides,
-		FilesBelowThreshold: checkCoverageStatsBelowThreshold(current, thr.File, overrideRules),
+		Threshold:            thr,
+		MaxUncovered:         mu,
+		MaxUncoveredLines:    mul,
+		MaxIgnored:           cfg.MaxIgnored,
+		FilesAboveMaxIgnored: checkStatsAboveMaxIgnored(current, cfg.MaxIgnored.File),
+		PackagesAboveMaxIgnored: checkStatsAboveMaxIgnored(
+			packages, cfg.MaxIgnored.Package,
+		),
+		DiffThreshold:        cfg.Diff.Threshold,
+		HasFileOverrides:     hasFileOverrides,
+		HasPackageOverrides:  hasPackageOverrides,
+		HasFunctionOverrides: hasFunctionOverrides,
+		FilesBelowThreshold: checkCoverageStatsBelowThreshold(
+			current, thr.File, mu.File, mul.File, overrideRules,
+		),
 		PackagesBelowThreshold: checkCoverageStatsBelowThreshold(
-			makePackageStats(current), thr.Package, overrideRules,
+			packages, thr.Package, mu.Package, mul.Package, overrideRules,
+		),
+		FunctionsBelowThreshold: checkCoverageStatsBelowThreshold(
+			functions, thr.Function, 0, 0, funcOverrideRules,
+		),
+		FilesWithUncoveredLines:       coverage.StatsFilterWithUncoveredLines(current),
+		FilesWithMissingExplanations:  filesWithMissingExplanations,
+		FilesWithUnmatchedAnnotations: coverage.StatsFilterWithUnmatchedAnnotations(current),
+		ForceAnnotationComment:        cfg.ForceAnnotationComment,
+		FilesWithExpiredAnnotations:   coverage.StatsFilterWithExpiredAnnotations(current),
+		FilesWithInvalidAnnotations:   coverage.StatsFilterWithInvalidAnnotations(current),
+		FilesWithMissingIssues:        filesWithMissingIssues,
+		ForceAnnotationIssue:          cfg.ForceAnnotationIssue,
+		TotalStats:                    coverage.StatsCalcTotal(current),
+		HasBaseBreakdown:              len(base) > 0,
+		Diff:                          calculateStatsDiff(current, base),
+		DiffPercentage:                TotalPercentageDiff(current, base),
+		ColdBlockHits:                 cfg.ColdBlockHits,
+		FilesWithColdBlocks:           coverage.StatsColdBlocks(current, cfg.ColdBlockHits),
+		Files: statsWithThreshold(
+			current, thr.File, mu.File, mul.File, overrideRules,
+		),
+		Packages: statsWithThreshold(
+			packages, thr.Package, mu.Package, mul.Package, overrideRules,
 		),
-		FilesWithUncoveredLines:      coverage.StatsFilterWithUncoveredLines(current),
-		FilesWithMissingExplanations: filesWithMissingExplanations,
-		TotalStats:                   coverage.StatsCalcTotal(current),
-		HasBaseBreakdown:             len(base) > 0,
-		Diff:                         calculateStatsDiff(current, base),
-		DiffPercentage:               TotalPercentageDiff(current, base),
+		Functions: statsWithThreshold(
+			functions, thr.Function, 0, 0, funcOverrideRules,
+		),
+	}
+}
+
+// AnalyzeWithChangedLines analyzes coverage like Analyze, and additionally
+// checks coverage of changed lines (see LoadChangedLines) against new code threshold.
+// When changed lines are nil, result is the same as result of Analyze.
+func AnalyzeWithChangedLines(
+	cfg Config,
+	current, base []coverage.Stats,
+	changed patch.ChangedLines,
+) AnalyzeResult {
+	result := Analyze(cfg, current, base)
+	if changed != nil {
+		result.HasNewCode = true
+		result.NewCode = NewCodeStats(current, changed)
 	}
+
+	return result
 }
 
-func detectOverrides(overrides []Override) (bool, bool) {
+func detectOverrides(overrides []Override) (bool, bool, bool) {
 	hasFileOverrides := false
 	hasPackageOverrides := false
+	hasFunctionOverrides := false
 
 	for _, override := range overrides {
-		if strings.HasSuffix(override.Path, ".go") || strings.HasSuffix(override.Path, ".go$") {
+		switch {
+		case isFunctionOverride(override):
+			hasFunctionOverrides = true
+		case strings.HasSuffix(override.Path, ".go") || strings.HasSuffix(override.Path, ".go$"):
 			hasFileOverrides = true
-		} else {
+		default:
 			hasPackageOverrides = true
 		}
 	}
 
-	return hasFileOverrides, hasPackageOverrides
+	return hasFileOverrides, hasPackageOverrides, hasFunctionOverrides
 }
 
 func saveCoverageBreakdown(cfg Config, stats []coverage.Stats) error {
@@ -140,8 +222,68 @@ func saveCoverageBreakdown(cfg Config, stats []coverage.Stats) error {
 		return nil
 	}
 
+	data, err := coverage.BreakdownSerialize(coverage.Breakdown{
+		Header: newBreakdownHeader(cfg),
+		Stats:  stats,
+	})
+	if err != nil { // coverage-ignore
+		return fmt.Errorf("serializing breakdown failed: %w", err)
+	}
+
 	//nolint:mnd,wrapcheck,gosec // relax
-	return os.WriteFile(cfg.BreakdownFileName, coverage.StatsSerialize(stats), 0o644)
+	return os.WriteFile(cfg.BreakdownFileName, data, 0o644)
+}
+
+func newBreakdownHeader(cfg Config) coverage.BreakdownHeader {
+	return coverage.BreakdownHeader{
+		Version:     coverage.BreakdownVersion,
+		ToolVersion: toolVersion(),
+		Module:      coverage.ModulePath(cfg.SourceDir),
+		Commit:      commitSHA(defaultSourceDir(cfg.SourceDir)),
+		Timestamp:   time.Now().UTC(),
+	}
+}
+
+const toolModulePath = "github.com/vladopajic/go-test-coverage/v2"
+
+// toolVersion returns version of this tool, as recorded in build info of the binary.
+func toolVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok { // coverage-ignore
+		return ""
+	}
+
+	if info.Main.Path == toolModulePath {
+		return info.Main.Version
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path == toolModulePath {
+			return dep.Version
+		}
+	}
+
+	return ""
+}
+
+// commitSHA returns SHA of the commit for which coverage is checked. It is taken from
+// CI environment when available, otherwise it is resolved from git repository in dir.
+func commitSHA(dir string) string {
+	for _, env := range []string{"GITHUB_SHA", "CI_COMMIT_SHA"} {
+		if sha := os.Getenv(env); sha != "" {
+			return sha
+		}
+	}
+
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = dir
+
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(out))
 }
 
 func loadBaseCoverageBreakdown(cfg Config) ([]coverage.Stats, error) {
@@ -161,3 +303,26 @@ func loadBaseCoverageBreakdown(cfg Config) ([]coverage.Stats, error) {
 
 	return stats, nil
 }
+
+// LoadChangedLines returns lines changed in patch file or in git diff against
+// base revision, as set in diff config. It returns nil when neither is set.
+func LoadChangedLines(cfg Config) (patch.ChangedLines, error) {
+	if cfg.Diff.PatchFileName != "" {
+		return patch.FromFile(cfg.Diff.PatchFileName) //nolint:wrapcheck // err wrapped above
+	}
+
+	if cfg.Diff.GitBase != "" {
+		sourceDir := defaultSourceDir(cfg.SourceDir)
+		return patch.FromGit(sourceDir, cfg.Diff.GitBase) //nolint:wrapcheck // relax
+	}
+
+	return nil, nil
+}
+
+func defaultSourceDir(dir string) string {
+	if dir == "" {
+		return "."
+	}
+
+	return dir
+}
@@ -2,16 +2,23 @@ package testcoverage_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	. "github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage"
+	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/checkrun"
 	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/coverage"
 	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/logger"
+	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/patch"
 	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/path"
+	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/prcomment"
 	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/testdata"
 )
 
@@ -117,7 +124,7 @@ func TestCheck(t *testing.T) {
 		cfg := Config{
 			Profile:   profileOK,
 			Threshold: Threshold{File: 100},
-			Override:  []Override{{Threshold: 10, Path: "^pkg"}},
+			Override:  []Override{{Threshold: ptr(10.0), Path: "^pkg"}},
 			SourceDir: sourceDir,
 		}
 		pass, err := Check(buf, cfg)
@@ -136,7 +143,7 @@ func TestCheck(t *testing.T) {
 		cfg := Config{
 			Profile:   profileOK,
 			Threshold: Threshold{File: 10},
-			Override:  []Override{{Threshold: 100, Path: "^pkg"}},
+			Override:  []Override{{Threshold: ptr(100.0), Path: "^pkg"}},
 			SourceDir: sourceDir,
 		}
 		pass, err := Check(buf, cfg)
@@ -159,7 +166,7 @@ func TestCheck(t *testing.T) {
 		cfg := Config{
 			Profile:   profileOK,
 			Threshold: Threshold{File: 70},
-			Override:  []Override{{Threshold: 50, Path: "pkg/testcoverage/badgestorer/github.go"}},
+			Override:  []Override{{Threshold: ptr(50.0), Path: "pkg/testcoverage/badgestorer/github.go"}},
 			SourceDir: sourceDir,
 		}
 		pass, err := Check(buf, cfg)
@@ -178,7 +185,7 @@ func TestCheck(t *testing.T) {
 		cfg := Config{
 			Profile:   profileOK,
 			Threshold: Threshold{File: 70},
-			Override:  []Override{{Threshold: 80, Path: "pkg/testcoverage/badgestorer/github.go"}},
+			Override:  []Override{{Threshold: ptr(80.0), Path: "pkg/testcoverage/badgestorer/github.go"}},
 			SourceDir: sourceDir,
 		}
 		pass, err := Check(buf, cfg)
@@ -212,6 +219,278 @@ func TestCheck(t *testing.T) {
 		assert.Contains(t, err.Error(), "failed to generate and save badge")
 	})
 
+	t.Run("valid profile - html report", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:    profileOK,
+			HTMLReport: t.TempDir() + "/report.html",
+			SourceDir:  sourceDir,
+			Threshold:  Threshold{File: 70},
+		}
+		pass, err := Check(buf, cfg)
+		assert.False(t, pass)
+		assert.NoError(t, err)
+
+		contentBytes, err := os.ReadFile(cfg.HTMLReport)
+		assert.NoError(t, err)
+
+		content := string(contentBytes)
+		assert.Contains(t, content, "<!DOCTYPE html>")
+		assert.NotContains(t, content, "<link") // report should be self-contained
+		assert.NotContains(t, content, "<script")
+		assert.Contains(t, content, "File coverage threshold (70%) satisfied:")
+		assert.Contains(t, content, "pkg/testcoverage/badgestorer/github.go")
+		assert.Contains(t, content, `class="uncovered"`)
+		assert.Contains(t, content, `class="ignored"`)
+	})
+
+	t.Run("valid profile - fail invalid html report file", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:    profileOK,
+			HTMLReport: t.TempDir(), // should failed because this is dir
+			SourceDir:  sourceDir,
+		}
+		pass, err := Check(buf, cfg)
+		assert.False(t, pass)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to save html report")
+	})
+
+	t.Run("valid profile - cobertura report", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:       profileOK,
+			CoberturaFile: t.TempDir() + "/cobertura.xml",
+			SourceDir:     sourceDir,
+		}
+		pass, err := Check(buf, cfg)
+		assert.True(t, pass)
+		assert.NoError(t, err)
+
+		contentBytes, err := os.ReadFile(cfg.CoberturaFile)
+		assert.NoError(t, err)
+		assert.Contains(t, string(contentBytes), `filename="pkg/testcoverage/badgestorer/github.go"`)
+	})
+
+	t.Run("valid profile - fail invalid cobertura file", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:       profileOK,
+			CoberturaFile: t.TempDir(), // should failed because this is dir
+			SourceDir:     sourceDir,
+		}
+		pass, err := Check(buf, cfg)
+		assert.False(t, pass)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to save cobertura report")
+	})
+
+	t.Run("valid profile - fail pr comment", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:   profileOK,
+			SourceDir: sourceDir,
+			PRComment: prcomment.Github{
+				Token:       "token",
+				Owner:       "owner",
+				Repository:  "repo",
+				PullRequest: 1,
+				BaseURL:     "://invalid", // should fail because url is invalid
+			},
+		}
+		pass, err := Check(buf, cfg)
+		assert.False(t, pass)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to save pr-comment report")
+	})
+
+	t.Run("valid profile - fail check run", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:   profileOK,
+			SourceDir: sourceDir,
+			CheckRun: checkrun.Github{
+				Token:      "token",
+				Owner:      "owner",
+				Repository: "repo",
+				HeadSHA:    "abc",
+				BaseURL:    "://invalid", // should fail because url is invalid
+			},
+		}
+		pass, err := Check(buf, cfg)
+		assert.False(t, pass)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to save github-check-run report")
+	})
+
+	t.Run("valid profile - lcov report", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:   profileOK,
+			LcovFile:  t.TempDir() + "/lcov.info",
+			SourceDir: sourceDir,
+		}
+		pass, err := Check(buf, cfg)
+		assert.True(t, pass)
+		assert.NoError(t, err)
+
+		contentBytes, err := os.ReadFile(cfg.LcovFile)
+		assert.NoError(t, err)
+		assert.Contains(t, string(contentBytes), "pkg/testcoverage/badgestorer/github.go\nFN:20,GitPublicURL\n")
+		assert.Equal(t,
+			strings.Count(string(contentBytes), "SF:"),
+			strings.Count(string(contentBytes), "end_of_record"),
+		)
+	})
+
+	t.Run("valid profile - fail invalid lcov file", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:   profileOK,
+			LcovFile:  t.TempDir(), // should failed because this is dir
+			SourceDir: sourceDir,
+		}
+		pass, err := Check(buf, cfg)
+		assert.False(t, pass)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to save lcov report")
+	})
+
+	t.Run("valid profile - json report", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:    profileOK,
+			JSONReport: t.TempDir() + "/report.json",
+			SourceDir:  sourceDir,
+			Threshold:  Threshold{File: 100},
+		}
+		pass, err := Check(buf, cfg)
+		assert.False(t, pass)
+		assert.NoError(t, err)
+
+		contentBytes, err := os.ReadFile(cfg.JSONReport)
+		assert.NoError(t, err)
+
+		var report struct {
+			Version int  `json:"version"`
+			Pass    bool `json:"pass"`
+			Files   []struct {
+				Name      string `json:"name"`
+				Threshold int    `json:"threshold"`
+			} `json:"files"`
+		}
+		assert.NoError(t, json.Unmarshal(contentBytes, &report))
+		assert.Equal(t, JSONReportVersion, report.Version)
+		assert.False(t, report.Pass)
+		assert.NotEmpty(t, report.Files)
+		assert.Equal(t, 100, report.Files[0].Threshold)
+	})
+
+	t.Run("valid profile - fail invalid json report file", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:    profileOK,
+			JSONReport: t.TempDir(), // should failed because this is dir
+			SourceDir:  sourceDir,
+		}
+		pass, err := Check(buf, cfg)
+		assert.False(t, pass)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to save json report")
+	})
+
+	t.Run("valid profile - sarif report", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:             profileOK,
+			SarifFile:           t.TempDir() + "/report.sarif",
+			SarifUncoveredLines: true,
+			SourceDir:           sourceDir,
+		}
+		pass, err := Check(buf, cfg)
+		assert.True(t, pass)
+		assert.NoError(t, err)
+
+		contentBytes, err := os.ReadFile(cfg.SarifFile)
+		assert.NoError(t, err)
+		assert.Contains(t, string(contentBytes), `"ruleId": "uncovered-lines"`)
+		assert.Contains(t, string(contentBytes), `"uri": "pkg/testcoverage/badgestorer/github.go"`)
+	})
+
+	t.Run("valid profile - fail invalid sarif file", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:   profileOK,
+			SarifFile: t.TempDir(), // should failed because this is dir
+			SourceDir: sourceDir,
+		}
+		pass, err := Check(buf, cfg)
+		assert.False(t, pass)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to save sarif report")
+	})
+
+	t.Run("valid profile - junit report", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:   profileOK,
+			JUnitFile: t.TempDir() + "/junit.xml",
+			SourceDir: sourceDir,
+			Threshold: Threshold{File: 10, Total: 10},
+		}
+		pass, err := Check(buf, cfg)
+		assert.True(t, pass)
+		assert.NoError(t, err)
+
+		contentBytes, err := os.ReadFile(cfg.JUnitFile)
+		assert.NoError(t, err)
+		assert.Contains(t, string(contentBytes), `<testsuite name="coverage.total" tests="1" failures="0">`)
+		assert.Contains(t, string(contentBytes),
+			`<testcase name="pkg/testcoverage/badgestorer/github.go" classname="coverage.file">`)
+	})
+
+	t.Run("valid profile - fail invalid junit file", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:   profileOK,
+			JUnitFile: t.TempDir(), // should failed because this is dir
+			SourceDir: sourceDir,
+		}
+		pass, err := Check(buf, cfg)
+		assert.False(t, pass)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to save junit report")
+	})
+
 	t.Run("valid profile - fail invalid breakdown file", func(t *testing.T) {
 		t.Parallel()
 
@@ -244,9 +523,24 @@ func TestCheck(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotEmpty(t, contentBytes)
 
+		breakdown, err := coverage.BreakdownDeserialize(contentBytes)
+		assert.NoError(t, err)
+		assert.Equal(t, coverage.BreakdownVersion, breakdown.Header.Version)
+		assert.Equal(t, "github.com/vladopajic/go-test-coverage/v2", breakdown.Header.Module)
+		assert.False(t, breakdown.Header.Timestamp.IsZero())
+
 		stats, err := GenerateCoverageStats(cfg)
 		assert.NoError(t, err)
-		assert.Equal(t, coverage.StatsSerialize(stats), contentBytes)
+
+		assert.Len(t, breakdown.Stats, len(stats))
+
+		for i, s := range breakdown.Stats {
+			assert.Equal(t, stats[i].Name, s.Name)
+			assert.Equal(t, stats[i].Total, s.Total)
+			assert.Equal(t, stats[i].Covered, s.Covered)
+			assert.Equal(t, stats[i].UncoveredLines, s.UncoveredLines)
+			assert.Equal(t, stats[i].Functions, s.Functions)
+		}
 	})
 
 	t.Run("valid profile - invalid base breakdown file", func(t *testing.T) {
@@ -266,6 +560,52 @@ func TestCheck(t *testing.T) {
 		assert.Contains(t, err.Error(), "failed to load base coverage breakdown")
 	})
 
+	t.Run("valid profile - invalid patch file", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:   profileOK,
+			Diff:      Diff{PatchFileName: t.TempDir()}, // should failed because this is dir
+			SourceDir: sourceDir,
+		}
+		pass, err := Check(buf, cfg)
+		assert.False(t, pass)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to load changed lines")
+	})
+
+	t.Run("valid profile - new code threshold", func(t *testing.T) {
+		t.Parallel()
+
+		patchFile := t.TempDir() + "/changes.patch"
+		err := os.WriteFile(patchFile, []byte(
+			"+++ b/pkg/testcoverage/badgestorer/github.go\n@@ -0,0 +1,100 @@\n"+strings.Repeat("+\n", 100),
+		), 0o600)
+		assert.NoError(t, err)
+
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:   profileOK,
+			Threshold: Threshold{NewCode: 100},
+			Diff:      Diff{PatchFileName: patchFile},
+			SourceDir: sourceDir,
+		}
+		pass, err := Check(buf, cfg)
+		assert.False(t, pass)
+		assert.NoError(t, err)
+		assertHumanReport(t, buf.String(), 0, 1)
+		assert.Contains(t, buf.String(), "New code test coverage:")
+		assert.Contains(t, buf.String(), "Changed lines without coverage:")
+
+		buf = &bytes.Buffer{}
+		cfg.Threshold.NewCode = 0
+		pass, err = Check(buf, cfg)
+		assert.True(t, pass)
+		assert.NoError(t, err)
+		assertHumanReport(t, buf.String(), 0, 0)
+	})
+
 	t.Run("valid profile - fail when missing explanations", func(t *testing.T) {
 		t.Parallel()
 
@@ -295,6 +635,21 @@ func TestCheck(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotContains(t, buf.String(), "Files with missing explanations for coverage-ignore")
 	})
+
+	t.Run("valid profile - fail when missing issue references", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:              profileOK,
+			SourceDir:            sourceDir,
+			ForceAnnotationIssue: true,
+		}
+		pass, err := Check(buf, cfg)
+		assert.False(t, pass)
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), "Files with missing issue reference for coverage-ignore")
+	})
 }
 
 func TestCheckDiff(t *testing.T) {
@@ -377,7 +732,7 @@ func TestCheckDiff(t *testing.T) {
 
 	tmpFile, err := os.CreateTemp(t.TempDir(), brakedownFileEdited)
 	assert.NoError(t, err)
-	_, err = tmpFile.Write(coverage.StatsSerialize(base))
+	_, err = tmpFile.Write(coverage.StatsSerialize(base)) //nolint:staticcheck // legacy format
 	assert.NoError(t, err)
 
 	// check should now pass since difference has increased
@@ -400,6 +755,87 @@ func TestCheckDiff(t *testing.T) {
 	assertDiffThreshold(t, buf.String(), *cfg.Diff.Threshold, true)
 }
 
+func TestCheckRatchet(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		return
+	}
+
+	ratchetFile := t.TempDir() + "/ratchet.testcoverage"
+
+	// should fail since ratchet file does not exist
+	cfg := Config{
+		Profile:   profileOK,
+		SourceDir: sourceDir,
+		Ratchet:   Ratchet{FileName: ratchetFile},
+	}
+	pass, err := Check(&bytes.Buffer{}, cfg)
+	assert.False(t, pass)
+	assert.ErrorContains(t, err, "failed to load ratchet")
+
+	// should create ratchet file on first update
+	cfg.Ratchet.Update = true
+	buf := &bytes.Buffer{}
+	pass, err = Check(buf, cfg)
+	assert.True(t, pass)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "Ratchet coverage satisfied:\tPASS")
+
+	stored := readRatchet(t, ratchetFile)
+	assert.NotEmpty(t, stored.Files)
+	assert.NotEmpty(t, stored.Packages)
+	assert.NotZero(t, stored.Total.Total)
+	assert.Less(t, stored.Total.Covered, stored.Total.Total)
+
+	// should pass since coverage is the same
+	cfg.Ratchet.Update = false
+	pass, err = Check(&bytes.Buffer{}, cfg)
+	assert.True(t, pass)
+	assert.NoError(t, err)
+
+	// should fail since stored total coverage is higher,
+	// while files which no longer exist are ignored
+	raised := stored
+	raised.Total.Covered = raised.Total.Total
+	raised.Files = append(raised.Files, coverage.Stats{Name: prefix + "/deleted.go", Total: 1, Covered: 1})
+	writeRatchet(t, ratchetFile, raised)
+
+	buf = &bytes.Buffer{}
+	pass, err = Check(buf, cfg)
+	assert.False(t, pass)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "Ratchet coverage satisfied:\tFAIL")
+	assert.Contains(t, buf.String(), "regressed:")
+	assert.NotContains(t, buf.String(), "deleted.go")
+
+	// should pass when drop in coverage is within tolerance
+	cfg.Ratchet.Tolerance = 100
+	pass, err = Check(&bytes.Buffer{}, cfg)
+	assert.True(t, pass)
+	assert.NoError(t, err)
+
+	// update keeps higher stored values and drops deleted files
+	cfg.Ratchet.Update = true
+	_, err = Check(&bytes.Buffer{}, cfg)
+	assert.NoError(t, err)
+
+	updated := readRatchet(t, ratchetFile)
+	assert.Equal(t, raised.Total.Covered, updated.Total.Covered)
+	assert.Equal(t, stored.Files, updated.Files)
+	assert.Equal(t, stored.Packages, updated.Packages)
+
+	// should fail since ratchet file is not valid
+	cfg = Config{
+		Profile:   profileOK,
+		SourceDir: sourceDir,
+		Ratchet:   Ratchet{FileName: path.NormalizeForOS(breakdownOK)},
+	}
+	pass, err = Check(&bytes.Buffer{}, cfg)
+	assert.False(t, pass)
+	assert.ErrorContains(t, err, "failed to load ratchet")
+}
+
 //nolint:paralleltest // must not be parallel because it uses env
 func TestCheckNoParallel(t *testing.T) {
 	if testing.Short() {
@@ -445,6 +881,9 @@ func TestCheckNoParallel(t *testing.T) {
 		testFile := t.TempDir() + "/ga.output"
 		t.Setenv(GaOutputFileEnv, testFile)
 
+		summaryFile := t.TempDir() + "/summary.md"
+		t.Setenv(GaStepSummaryEnv, summaryFile)
+
 		buf := &bytes.Buffer{}
 		cfg := Config{
 			Profile:            profileOK,
@@ -459,6 +898,87 @@ func TestCheckNoParallel(t *testing.T) {
 		assertHumanReport(t, buf.String(), 0, 1)
 		assertGithubOutputValues(t, testFile)
 		assertHasUncoveredLinesInfo(t, buf.String(), []string{})
+
+		contentBytes, err := os.ReadFile(summaryFile)
+		assert.NoError(t, err)
+		assert.Contains(t, string(contentBytes), "| Total coverage threshold | 100% | :x: FAIL |")
+	})
+
+	t.Run("ok fail; with github output file and check run", func(t *testing.T) {
+		t.Setenv(GaOutputFileEnv, t.TempDir()+"/ga.output")
+		t.Setenv(GaStepSummaryEnv, "")
+
+		var checkRuns int
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			checkRuns++
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id":1,"html_url":"https://github.com/owner/repo/runs/1"}`)) //nolint:errcheck // relax
+		}))
+		defer srv.Close()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:            profileOK,
+			GithubActionOutput: true,
+			Threshold:          Threshold{Total: 100},
+			SourceDir:          sourceDir,
+			CheckRun: checkrun.Github{
+				Token:      "token",
+				Owner:      "owner",
+				Repository: "repo",
+				HeadSHA:    "abc",
+				BaseURL:    srv.URL,
+			},
+		}
+		pass, err := Check(buf, cfg)
+		assert.False(t, pass)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, checkRuns)
+		assertGithubActionErrorsCount(t, buf.String(), 0)
+		assertHumanReport(t, buf.String(), 0, 1)
+		assert.Contains(t, buf.String(), "GitHub check run created: https://github.com/owner/repo/runs/1")
+	})
+
+	t.Run("ok fail; with gitlab output", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv(GlOutputDirEnv, dir)
+
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:      profileOK,
+			GitlabOutput: true,
+			Threshold:    Threshold{File: 70},
+			SourceDir:    sourceDir,
+		}
+		pass, err := Check(buf, cfg)
+		assert.False(t, pass)
+		assert.NoError(t, err)
+		assert.Regexp(t, `(?m)^Coverage: \d+\.\d%$`, buf.String())
+
+		contentBytes, err := os.ReadFile(filepath.Join(dir, GlCodeQualityFile))
+		assert.NoError(t, err)
+		assert.Contains(t, string(contentBytes), `"path": "pkg/testcoverage/badgestorer/github.go"`)
+
+		contentBytes, err = os.ReadFile(filepath.Join(dir, GlDotenvFile))
+		assert.NoError(t, err)
+		assert.Contains(t, string(contentBytes), "TOTAL_COVERAGE=")
+	})
+
+	t.Run("fail; invalid gitlab output dir", func(t *testing.T) {
+		t.Setenv(GlOutputDirEnv, t.TempDir()+"/not-exist")
+
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:      profileOK,
+			GitlabOutput: true,
+			SourceDir:    sourceDir,
+		}
+		pass, err := Check(buf, cfg)
+		assert.False(t, pass)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed setting gitlab output")
 	})
 
 	t.Run("logger has output", func(t *testing.T) {
@@ -526,6 +1046,134 @@ func Test_Analyze(t *testing.T) {
 		assert.False(t, result.Pass())
 	})
 
+	t.Run("fractional thresholds", func(t *testing.T) {
+		t.Parallel()
+
+		stats := []coverage.Stats{
+			{Name: prefix + "/foo.go", Total: 10000, Covered: 7996},
+		}
+
+		// coverage (79.96%) is compared without rounding
+		result := Analyze(Config{Threshold: Threshold{File: 80, Total: 80}}, stats, nil)
+		assert.False(t, result.Pass())
+		assert.False(t, result.MeetsTotalCoverage())
+		assert.Len(t, result.FilesBelowThreshold, 1)
+
+		result = Analyze(Config{Threshold: Threshold{File: 79.95, Total: 79.95}}, stats, nil)
+		assert.True(t, result.Pass())
+
+		result = Analyze(Config{
+			Threshold: Threshold{File: 70},
+			Override:  []Override{{Path: "foo.go$", Threshold: ptr(79.97)}},
+		}, stats, nil)
+		assert.False(t, result.Pass())
+		assert.InDelta(t, 79.97, result.FilesBelowThreshold[0].Threshold, 0)
+	})
+
+	t.Run("max uncovered statements", func(t *testing.T) {
+		t.Parallel()
+
+		stats := []coverage.Stats{
+			{Name: prefix + "/a/foo.go", Total: 1000, Covered: 990},
+			{Name: prefix + "/a/bar.go", Total: 10, Covered: 7},
+		}
+
+		// percentage threshold is met, while limit of uncovered statements is not
+		result := Analyze(Config{
+			Threshold:    Threshold{File: 50},
+			MaxUncovered: MaxUncovered{File: 5},
+		}, stats, nil)
+		assert.False(t, result.Pass())
+		assert.True(t, result.HasFileThreshold())
+		assert.Equal(t, []string{prefix + "/a/foo.go"},
+			coverage.StatsPluckName(result.FilesBelowThreshold))
+
+		result = Analyze(Config{MaxUncovered: MaxUncovered{Package: 12}}, stats, nil)
+		assert.False(t, result.Pass())
+		assert.Len(t, result.PackagesBelowThreshold, 1)
+
+		result = Analyze(Config{MaxUncovered: MaxUncovered{Total: 13}}, stats, nil)
+		assert.True(t, result.Pass())
+
+		result = Analyze(Config{MaxUncovered: MaxUncovered{Total: 12}}, stats, nil)
+		assert.False(t, result.Pass())
+		assert.False(t, result.MeetsTotalCoverage())
+
+		// limit from override rule applies to matching files
+		result = Analyze(Config{
+			MaxUncovered: MaxUncovered{File: 5},
+			Override: []Override{
+				{Path: "foo.go$", MaxUncovered: ptr(10)},
+				{Path: "bar.go$", MaxUncovered: ptr(2)},
+			},
+		}, stats, nil)
+		assert.False(t, result.Pass())
+		assert.Equal(t, []string{prefix + "/a/bar.go"},
+			coverage.StatsPluckName(result.FilesBelowThreshold))
+		assert.Equal(t, 2, result.FilesBelowThreshold[0].MaxUncovered)
+
+		// override rule which sets only threshold keeps default limit
+		result = Analyze(Config{
+			MaxUncovered: MaxUncovered{File: 5},
+			Override:     []Override{{Path: "foo.go$", Threshold: ptr(50.0)}},
+		}, stats, nil)
+		assert.False(t, result.Pass())
+		assert.Equal(t, []string{prefix + "/a/foo.go"},
+			coverage.StatsPluckName(result.FilesBelowThreshold))
+		assert.Equal(t, 5, result.FilesBelowThreshold[0].MaxUncovered)
+		assert.InDelta(t, 50, result.FilesBelowThreshold[0].Threshold, 0)
+
+		// override rule which sets only limit keeps default threshold
+		result = Analyze(Config{
+			Threshold: Threshold{File: 95},
+			Override:  []Override{{Path: "bar.go$", MaxUncovered: ptr(5)}},
+		}, stats, nil)
+		assert.False(t, result.Pass())
+		assert.Equal(t, []string{prefix + "/a/bar.go"},
+			coverage.StatsPluckName(result.FilesBelowThreshold))
+		assert.InDelta(t, 95, result.FilesBelowThreshold[0].Threshold, 0)
+	})
+
+	t.Run("max uncovered lines", func(t *testing.T) {
+		t.Parallel()
+
+		stats := []coverage.Stats{
+			{Name: prefix + "/a/foo.go", Total: 10, Covered: 6, UncoveredLines: []int{1, 2, 3}},
+			{Name: prefix + "/a/bar.go", Total: 10, Covered: 8, UncoveredLines: []int{4}},
+		}
+
+		// limit of uncovered statements is met, while limit of uncovered lines is not
+		result := Analyze(Config{
+			MaxUncovered:      MaxUncovered{File: 4},
+			MaxUncoveredLines: MaxUncovered{File: 2},
+		}, stats, nil)
+		assert.False(t, result.Pass())
+		assert.True(t, result.HasFileThreshold())
+		assert.Equal(t, []string{prefix + "/a/foo.go"},
+			coverage.StatsPluckName(result.FilesBelowThreshold))
+
+		result = Analyze(Config{MaxUncoveredLines: MaxUncovered{Package: 4}}, stats, nil)
+		assert.True(t, result.Pass())
+
+		result = Analyze(Config{MaxUncoveredLines: MaxUncovered{Package: 3}}, stats, nil)
+		assert.False(t, result.Pass())
+		assert.Len(t, result.PackagesBelowThreshold, 1)
+
+		result = Analyze(Config{MaxUncoveredLines: MaxUncovered{Total: 4}}, stats, nil)
+		assert.True(t, result.Pass())
+
+		result = Analyze(Config{MaxUncoveredLines: MaxUncovered{Total: 3}}, stats, nil)
+		assert.False(t, result.Pass())
+		assert.False(t, result.MeetsTotalCoverage())
+
+		// limit from override rule applies to matching files
+		result = Analyze(Config{
+			MaxUncoveredLines: MaxUncovered{File: 2},
+			Override:          []Override{{Path: "foo.go$", MaxUncoveredLines: ptr(3)}},
+		}, stats, nil)
+		assert.True(t, result.Pass())
+	})
+
 	t.Run("files coverage above threshold", func(t *testing.T) {
 		t.Parallel()
 
@@ -596,6 +1244,29 @@ func Test_Analyze(t *testing.T) {
 		assert.Equal(t, 0.0, result.DiffPercentage) //nolint:testifylint //relax
 	})
 
+	t.Run("diff lines", func(t *testing.T) {
+		t.Parallel()
+
+		base := []coverage.Stats{{Name: "foo", Total: 10, Covered: 8, UncoveredLines: []int{1, 2}}}
+		stats := []coverage.Stats{{Name: "foo", Total: 10, Covered: 7, UncoveredLines: []int{2, 4, 5}}}
+
+		result := Analyze(Config{}, stats, base)
+		assert.Len(t, result.Diff, 1)
+		assert.Equal(t, []int{4, 5}, result.Diff[0].NewlyUncoveredLines)
+		assert.Equal(t, []int{1}, result.Diff[0].NewlyCoveredLines)
+
+		// lines are compared even when number of uncovered statements did not change
+		stats = []coverage.Stats{{Name: "foo", Total: 10, Covered: 8, UncoveredLines: []int{2, 6}}}
+
+		result = Analyze(Config{}, stats, base)
+		assert.Len(t, result.Diff, 1)
+		assert.Equal(t, []int{6}, result.Diff[0].NewlyUncoveredLines)
+		assert.Equal(t, []int{1}, result.Diff[0].NewlyCoveredLines)
+
+		result = Analyze(Config{}, base, base)
+		assert.Empty(t, result.Diff)
+	})
+
 	t.Run("diff below threshold", func(t *testing.T) {
 		t.Parallel()
 
@@ -643,6 +1314,105 @@ func Test_Analyze(t *testing.T) {
 		assert.Equal(t, 0.01, result.DiffPercentage) //nolint:testifylint //relax
 	})
 
+	t.Run("function coverage below threshold", func(t *testing.T) {
+		t.Parallel()
+
+		stats := []coverage.Stats{
+			{
+				Name: prefix + "/foo.go", Total: 10, Covered: 9,
+				Functions: []coverage.FuncStats{
+					{Name: "Foo", Total: 9, Covered: 9},
+					{Name: "(*T).Bar", Total: 1, Covered: 0},
+				},
+			},
+			{
+				Name: prefix + "/bar.go", Total: 10, Covered: 5,
+				Functions: []coverage.FuncStats{
+					{Name: "Bar", Total: 10, Covered: 5},
+				},
+			},
+		}
+
+		result := Analyze(Config{Threshold: Threshold{File: 50, Function: 50}}, stats, nil)
+		assert.False(t, result.Pass())
+		assert.Empty(t, result.FilesBelowThreshold)
+		assert.Equal(t, []string{prefix + "/foo.go:(*T).Bar"},
+			coverage.StatsPluckName(result.FunctionsBelowThreshold))
+
+		// function override should not apply to files or packages
+		cfg := Config{
+			Threshold: Threshold{File: 50, Function: 50},
+			Override: []Override{
+				{Path: `foo\.go:\(\*T\)\.Bar$`, Threshold: ptr(0.0)},
+				{Path: `bar\.go:Bar$`, Threshold: ptr(60.0)},
+			},
+		}
+		result = Analyze(cfg, stats, nil)
+		assert.False(t, result.Pass())
+		assert.True(t, result.HasFunctionOverrides)
+		assert.False(t, result.HasFileOverrides)
+		assert.False(t, result.HasPackageOverrides)
+		assert.Empty(t, result.FilesBelowThreshold)
+		assert.Equal(t, []string{prefix + "/bar.go:Bar"},
+			coverage.StatsPluckName(result.FunctionsBelowThreshold))
+		assert.InDelta(t, 60.0, result.FunctionsBelowThreshold[0].Threshold, 0)
+	})
+
+	t.Run("new code stats", func(t *testing.T) {
+		t.Parallel()
+
+		stats := []coverage.Stats{
+			{
+				Name:           "pkg/foo.go",
+				CoveredLines:   []int{1, 2, 3},
+				UncoveredLines: []int{3, 4},
+			},
+			{Name: "pkg/bar.go", CoveredLines: []int{1}},
+			{Name: "pkg/baz.go", CoveredLines: []int{1}},
+		}
+		changed := patch.ChangedLines{
+			"module/pkg/foo.go": {2, 3, 4, 5},
+			"pkg/bar.go":        {2},
+		}
+
+		newCode := NewCodeStats(stats, changed)
+		assert.Equal(t, []coverage.Stats{
+			{Name: "pkg/foo.go", Total: 3, Covered: 1, UncoveredLines: []int{3, 4}},
+		}, newCode)
+
+		// library users get new code stats with analyze step
+		result := AnalyzeWithChangedLines(Config{}, stats, nil, changed)
+		assert.True(t, result.HasNewCode)
+		assert.Equal(t, newCode, result.NewCode)
+
+		result = AnalyzeWithChangedLines(Config{}, stats, nil, nil)
+		assert.False(t, result.HasNewCode)
+		assert.Empty(t, result.NewCode)
+	})
+
+	t.Run("new code threshold", func(t *testing.T) {
+		t.Parallel()
+
+		result := AnalyzeResult{
+			Threshold:  Threshold{NewCode: 50},
+			HasNewCode: true,
+			NewCode:    []coverage.Stats{{Name: "foo.go", Total: 4, Covered: 2}},
+		}
+		assert.True(t, result.MeetsNewCodeThreshold())
+		assert.True(t, result.Pass())
+
+		result.Threshold.NewCode = 51
+		assert.False(t, result.MeetsNewCodeThreshold())
+		assert.False(t, result.Pass())
+
+		result.HasNewCode = false
+		assert.True(t, result.MeetsNewCodeThreshold())
+
+		result.HasNewCode = true
+		result.NewCode = nil
+		assert.True(t, result.MeetsNewCodeThreshold())
+	})
+
 	t.Run("missing explanations for coverage-ignore", func(t *testing.T) {
 		t.Parallel()
 
@@ -0,0 +1,174 @@
+package checkrun
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v88/github"
+)
+
+const (
+	// MaxAnnotationsPerRequest is maximum number of annotations which GitHub
+	// accepts in single request, so annotations are sent in batches of this size.
+	MaxAnnotationsPerRequest = 50
+
+	// MaxSummaryLength is maximum length of check run summary.
+	MaxSummaryLength = 65535
+
+	DefaultName = "go-test-coverage"
+)
+
+const (
+	LevelNotice  = "notice"
+	LevelWarning = "warning"
+	LevelFailure = "failure"
+)
+
+type Github struct {
+	Token      string
+	Owner      string
+	Repository string
+	HeadSHA    string
+
+	// Name of the check run, DefaultName is used when it is not set.
+	Name string
+
+	// BaseURL of GitHub API, default is used when it is not set.
+	BaseURL string
+}
+
+type Run struct {
+	Title       string
+	Summary     string
+	Success     bool
+	Annotations []Annotation
+}
+
+type Annotation struct {
+	Path      string
+	StartLine int
+	EndLine   int
+	Level     string
+	Title     string
+	Message   string
+}
+
+// Create creates completed check run with annotations on commit. Run is created
+// with the first batch of annotations and the remaining batches are added with
+// updates of the run, which is completed with the last request. It returns URL
+// of created check run.
+func Create(cfg Github, run Run) (string, error) {
+	client, err := newClient(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	ctx := context.Background()
+	name := cfg.Name
+	if name == "" {
+		name = DefaultName
+	}
+
+	batches := batchAnnotations(run.Annotations)
+	last := len(batches) - 1
+
+	createOpts := github.CreateCheckRunOptions{
+		Name:    name,
+		HeadSHA: cfg.HeadSHA,
+		Status:  github.Ptr("in_progress"),
+		Output:  makeOutput(run, batches[0]),
+	}
+	if last == 0 {
+		createOpts.Status, createOpts.Conclusion, createOpts.CompletedAt = completed(run)
+	}
+
+	cr, _, err := client.Checks.CreateCheckRun(ctx, cfg.Owner, cfg.Repository, createOpts)
+	if err != nil {
+		return "", fmt.Errorf("create check run: %w", err)
+	}
+
+	for i := 1; i <= last; i++ {
+		updateOpts := github.UpdateCheckRunOptions{
+			Name:   name,
+			Output: makeOutput(run, batches[i]),
+		}
+		if i == last {
+			updateOpts.Status, updateOpts.Conclusion, updateOpts.CompletedAt = completed(run)
+		}
+
+		_, _, err = client.Checks.UpdateCheckRun(ctx, cfg.Owner, cfg.Repository, cr.GetID(), updateOpts)
+		if err != nil {
+			return "", fmt.Errorf("update check run: %w", err)
+		}
+	}
+
+	return cr.GetHTMLURL(), nil
+}
+
+func newClient(cfg Github) (*github.Client, error) {
+	opts := []github.ClientOptionsFunc{github.WithAuthToken(cfg.Token)}
+	if cfg.BaseURL != "" {
+		opts = append(opts, github.WithURLs(&cfg.BaseURL, nil))
+	}
+
+	client, err := github.NewClient(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("create github client: %w", err)
+	}
+
+	return client, nil
+}
+
+// batchAnnotations splits annotations in batches which can be sent in single
+// request. There is always at least one batch, which may be empty.
+func batchAnnotations(annotations []Annotation) [][]Annotation {
+	batches := [][]Annotation{nil}
+
+	for i, a := range annotations {
+		if i > 0 && i%MaxAnnotationsPerRequest == 0 {
+			batches = append(batches, nil)
+		}
+
+		batches[len(batches)-1] = append(batches[len(batches)-1], a)
+	}
+
+	return batches
+}
+
+func makeOutput(run Run, annotations []Annotation) *github.CheckRunOutput {
+	summary := run.Summary
+	if len(summary) > MaxSummaryLength {
+		const suffix = "\n\n... summary is truncated"
+		summary = summary[:MaxSummaryLength-len(suffix)] + suffix
+	}
+
+	out := &github.CheckRunOutput{
+		Title:       &run.Title,
+		Summary:     &summary,
+		Annotations: make([]*github.CheckRunAnnotation, len(annotations)),
+	}
+
+	for i, a := range annotations {
+		endLine := max(a.EndLine, a.StartLine)
+		out.Annotations[i] = &github.CheckRunAnnotation{
+			Path:            github.Ptr(a.Path),
+			StartLine:       github.Ptr(a.StartLine),
+			EndLine:         &endLine,
+			AnnotationLevel: github.Ptr(a.Level),
+			Title:           github.Ptr(a.Title),
+			Message:         github.Ptr(a.Message),
+		}
+	}
+
+	return out
+}
+
+func completed(run Run) (*string, *string, *github.Timestamp) {
+	conclusion := "failure"
+	if run.Success {
+		conclusion = "success"
+	}
+
+	return github.Ptr("completed"), &conclusion, &github.Timestamp{Time: time.Now()}
+}
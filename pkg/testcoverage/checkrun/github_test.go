@@ -0,0 +1,230 @@
+package checkrun_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/checkrun"
+)
+
+func Test_Create(t *testing.T) {
+	t.Parallel()
+
+	t.Run("without annotations", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newGithubServer(t)
+
+		url, err := Create(srv.config(), Run{Title: "title", Summary: "summary", Success: true})
+		assert.NoError(t, err)
+		assert.Equal(t, srv.URL+"/owner/repo/runs/1", url)
+
+		reqs := srv.requests()
+		assert.Len(t, reqs, 1)
+		assert.Equal(t, http.MethodPost, reqs[0].Method)
+		assert.Equal(t, DefaultName, reqs[0].Body.Name)
+		assert.Equal(t, "abc", reqs[0].Body.HeadSHA)
+		assert.Equal(t, "completed", reqs[0].Body.Status)
+		assert.Equal(t, "success", reqs[0].Body.Conclusion)
+		assert.NotEmpty(t, reqs[0].Body.CompletedAt)
+		assert.Equal(t, "title", reqs[0].Body.Output.Title)
+		assert.Equal(t, "summary", reqs[0].Body.Output.Summary)
+		assert.Empty(t, reqs[0].Body.Output.Annotations)
+	})
+
+	t.Run("batched annotations", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newGithubServer(t)
+		cfg := srv.config()
+		cfg.Name = "coverage"
+
+		run := Run{Title: "title", Summary: "summary", Success: false}
+		for i := range 120 {
+			run.Annotations = append(run.Annotations, Annotation{
+				Path:      "foo.go",
+				StartLine: i + 1,
+				Level:     LevelFailure,
+				Title:     "title",
+				Message:   "message",
+			})
+		}
+
+		_, err := Create(cfg, run)
+		assert.NoError(t, err)
+
+		reqs := srv.requests()
+		assert.Len(t, reqs, 3)
+
+		assert.Equal(t, http.MethodPost, reqs[0].Method)
+		assert.Equal(t, "in_progress", reqs[0].Body.Status)
+		assert.Empty(t, reqs[0].Body.Conclusion)
+		assert.Len(t, reqs[0].Body.Output.Annotations, MaxAnnotationsPerRequest)
+
+		assert.Equal(t, http.MethodPatch, reqs[1].Method)
+		assert.Equal(t, "/repos/owner/repo/check-runs/1", reqs[1].Path)
+		assert.Empty(t, reqs[1].Body.Status)
+		assert.Len(t, reqs[1].Body.Output.Annotations, MaxAnnotationsPerRequest)
+
+		assert.Equal(t, http.MethodPatch, reqs[2].Method)
+		assert.Equal(t, "completed", reqs[2].Body.Status)
+		assert.Equal(t, "failure", reqs[2].Body.Conclusion)
+		assert.Len(t, reqs[2].Body.Output.Annotations, 20)
+
+		for _, r := range reqs {
+			assert.Equal(t, "coverage", r.Body.Name)
+			assert.Equal(t, "summary", r.Body.Output.Summary)
+		}
+
+		a := reqs[2].Body.Output.Annotations[0]
+		assert.Equal(t, annotation{
+			Path:            "foo.go",
+			StartLine:       101,
+			EndLine:         101,
+			AnnotationLevel: LevelFailure,
+			Title:           "title",
+			Message:         "message",
+		}, a)
+	})
+
+	t.Run("truncated summary", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newGithubServer(t)
+
+		_, err := Create(srv.config(), Run{Summary: strings.Repeat("a", MaxSummaryLength+1)})
+		assert.NoError(t, err)
+
+		summary := srv.requests()[0].Body.Output.Summary
+		assert.Len(t, summary, MaxSummaryLength)
+		assert.True(t, strings.HasSuffix(summary, "summary is truncated"))
+	})
+}
+
+func Test_Create_Error(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid base url", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := Github{Token: "token", Owner: "owner", Repository: "repo", HeadSHA: "abc"}
+		cfg.BaseURL = "://invalid"
+
+		_, err := Create(cfg, Run{})
+		assert.Error(t, err)
+	})
+
+	t.Run("create check run", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newGithubServer(t)
+		srv.failOn = http.MethodPost
+
+		_, err := Create(srv.config(), Run{})
+		assert.ErrorContains(t, err, "create check run")
+	})
+
+	t.Run("update check run", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newGithubServer(t)
+		srv.failOn = http.MethodPatch
+
+		run := Run{Annotations: make([]Annotation, MaxAnnotationsPerRequest+1)}
+		_, err := Create(srv.config(), run)
+		assert.ErrorContains(t, err, "update check run")
+	})
+}
+
+type annotation struct {
+	Path            string `json:"path"`
+	StartLine       int    `json:"start_line"`
+	EndLine         int    `json:"end_line"`
+	AnnotationLevel string `json:"annotation_level"`
+	Title           string `json:"title"`
+	Message         string `json:"message"`
+}
+
+type checkRunBody struct {
+	Name        string `json:"name"`
+	HeadSHA     string `json:"head_sha"`
+	Status      string `json:"status"`
+	Conclusion  string `json:"conclusion"`
+	CompletedAt string `json:"completed_at"`
+	Output      struct {
+		Title       string       `json:"title"`
+		Summary     string       `json:"summary"`
+		Annotations []annotation `json:"annotations"`
+	} `json:"output"`
+}
+
+type request struct {
+	Method string
+	Path   string
+	Body   checkRunBody
+}
+
+// githubServer is minimal stand-in for GitHub API which records
+// requests made to check runs API.
+type githubServer struct {
+	*httptest.Server
+
+	mx     sync.Mutex
+	reqs   []request
+	failOn string
+}
+
+func newGithubServer(t *testing.T) *githubServer {
+	t.Helper()
+
+	s := &githubServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *githubServer) config() Github {
+	return Github{
+		Token:      "token",
+		Owner:      "owner",
+		Repository: "repo",
+		HeadSHA:    "abc",
+		BaseURL:    s.URL,
+	}
+}
+
+func (s *githubServer) requests() []request {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	return s.reqs
+}
+
+func (s *githubServer) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method == s.failOn {
+		http.Error(w, `{"message":"failure"}`, http.StatusInternalServerError)
+		return
+	}
+
+	req := request{Method: r.Method, Path: r.URL.Path}
+	if err := json.NewDecoder(r.Body).Decode(&req.Body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mx.Lock()
+	s.reqs = append(s.reqs, req)
+	s.mx.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	//nolint:errcheck // relax
+	fmt.Fprintf(w, `{"id":1,"html_url":"%s/owner/repo/runs/1"}`, s.URL)
+}
@@ -11,50 +11,124 @@ import (
 	yaml "gopkg.in/yaml.v3"
 
 	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/badgestorer"
+	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/checkrun"
+	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/prcomment"
 )
 
 const HiddenValue = "***"
 
 var (
 	ErrThresholdNotInRange         = errors.New("threshold must be in range [0 - 100]")
+	ErrMaxUncoveredNegative        = errors.New("max uncovered must not be negative")
 	ErrCoverageProfileNotSpecified = errors.New("coverage profile file not specified")
 	ErrRegExpNotValid              = errors.New("regular expression is not valid")
 	ErrCDNOptionNotSet             = errors.New("CDN options are not valid")
 	ErrGitOptionNotSet             = errors.New("git options are not valid")
+	ErrPRCommentOptionNotSet       = errors.New("pull request comment options are not valid")
+	ErrCheckRunOptionNotSet        = errors.New("check run options are not valid")
+	ErrDiffSourceConflict          = errors.New("only one source of changed lines can be set")
+	ErrColdBlockHitsNegative       = errors.New("cold block hits must not be negative")
+	ErrUnknownReportType           = errors.New("unknown report type")
+	ErrRatchetToleranceNegative    = errors.New("ratchet tolerance must not be negative")
+	ErrRatchetFileNotSet           = errors.New("ratchet file name must be set to update ratchet")
 )
 
 type Config struct {
-	Profile                string     `yaml:"profile"`
-	Debug                  bool       `yaml:"-"`
-	SourceDir              string     `yaml:"-"`
-	Threshold              Threshold  `yaml:"threshold"`
-	Override               []Override `yaml:"override,omitempty"`
-	Exclude                Exclude    `yaml:"exclude"`
-	BreakdownFileName      string     `yaml:"breakdown-file-name"`
-	GithubActionOutput     bool       `yaml:"github-action-output"`
-	Diff                   Diff       `yaml:"diff"`
-	Badge                  Badge      `yaml:"-"`
-	ForceAnnotationComment bool       `yaml:"force-annotation-comment"`
+	Profile                string           `yaml:"profile"`
+	Debug                  bool             `yaml:"-"`
+	SourceDir              string           `yaml:"-"`
+	Threshold              Threshold        `yaml:"threshold"`
+	MaxUncovered           MaxUncovered     `yaml:"max-uncovered"`
+	MaxUncoveredLines      MaxUncovered     `yaml:"max-uncovered-lines"`
+	MaxIgnored             MaxIgnored       `yaml:"max-ignored"`
+	Override               []Override       `yaml:"override,omitempty"`
+	Exclude                Exclude          `yaml:"exclude"`
+	BreakdownFileName      string           `yaml:"breakdown-file-name"`
+	GithubActionOutput     bool             `yaml:"github-action-output"`
+	GitlabOutput           bool             `yaml:"gitlab-output"`
+	Diff                   Diff             `yaml:"diff"`
+	Ratchet                Ratchet          `yaml:"ratchet"`
+	Badge                  Badge            `yaml:"-"`
+	PRComment              prcomment.Github `yaml:"-"`
+	CheckRun               checkrun.Github  `yaml:"-"`
+	ForceAnnotationComment bool             `yaml:"force-annotation-comment"`
+	ForceAnnotationIssue   bool             `yaml:"force-annotation-issue"`
+	ColdBlockHits          int              `yaml:"cold-block-hits"`
+	HTMLReport             string           `yaml:"html-report"`
+	CoberturaFile          string           `yaml:"cobertura-file"`
+	LcovFile               string           `yaml:"lcov-file"`
+	JSONReport             string           `yaml:"json-report"`
+	SarifFile              string           `yaml:"sarif-file"`
+	SarifUncoveredLines    bool             `yaml:"sarif-uncovered-lines"`
+	JUnitFile              string           `yaml:"junit-file"`
+	Reports                []Report         `yaml:"reports,omitempty"`
 }
 
+// Threshold holds minimum coverage (in percentage) of files, packages, total,
+// changed lines and functions. Thresholds may be fractional (e.g. 87.5), and
+// coverage is compared against them without rounding.
 type Threshold struct {
+	File     float64 `yaml:"file"`
+	Package  float64 `yaml:"package"`
+	Total    float64 `yaml:"total"`
+	NewCode  float64 `yaml:"new-code"`
+	Function float64 `yaml:"function"`
+}
+
+// MaxUncovered holds maximum number of uncovered statements (or lines) of files,
+// packages and total. It is checked next to percentage thresholds, where limit is
+// not checked when it is zero.
+type MaxUncovered struct {
 	File    int `yaml:"file"`
 	Package int `yaml:"package"`
 	Total   int `yaml:"total"`
 }
 
+// MaxIgnored holds maximum percentage of statements which can be ignored with
+// annotations, out of all statements. Limit is not checked when it is zero.
+type MaxIgnored struct {
+	File    int `yaml:"file"`
+	Package int `yaml:"package"`
+	Total   int `yaml:"total"`
+}
+
+// Report configures report made after analysis. Report is written to file
+// when it is set, otherwise it is written to standard output. Disabled report
+// is not made, which is used to turn off human report made by default.
+type Report struct {
+	Type     string `yaml:"type"`
+	File     string `yaml:"file,omitempty"`
+	Disabled bool   `yaml:"disabled,omitempty"`
+}
+
+// Override sets threshold and limits of uncovered statements and lines for
+// matching paths. Values which are not set (nil) are taken from defaults.
 type Override struct {
-	Threshold int    `yaml:"threshold"`
-	Path      string `yaml:"path"`
+	Threshold         *float64 `yaml:"threshold,omitempty"`
+	MaxUncovered      *int     `yaml:"max-uncovered,omitempty"`
+	MaxUncoveredLines *int     `yaml:"max-uncovered-lines,omitempty"`
+	Path              string   `yaml:"path"`
 }
 
 type Exclude struct {
-	Paths []string `yaml:"paths,omitempty"`
+	Paths     []string `yaml:"paths,omitempty"`
+	Generated bool     `yaml:"generated,omitempty"`
 }
 
 type Diff struct {
 	BaseBreakdownFileName string   `yaml:"base-breakdown-file-name"`
 	Threshold             *float64 `yaml:"threshold,omitempty"`
+	PatchFileName         string   `yaml:"patch-file-name,omitempty"`
+	GitBase               string   `yaml:"git-base,omitempty"`
+}
+
+// Ratchet configures ratchet file, which holds highest coverage reached by
+// files, packages and in total. Coverage may not drop below stored values by
+// more than tolerance (in percentage points).
+type Ratchet struct {
+	FileName  string  `yaml:"file-name,omitempty"`
+	Tolerance float64 `yaml:"tolerance,omitempty"`
+	Update    bool    `yaml:"-"`
 }
 
 type Badge struct {
@@ -81,6 +155,14 @@ func (c Config) Redacted() Config {
 		r.Badge.Git.Token = HiddenValue
 	}
 
+	if r.PRComment.Token != "" {
+		r.PRComment.Token = HiddenValue
+	}
+
+	if r.CheckRun.Token != "" {
+		r.CheckRun.Token = HiddenValue
+	}
+
 	return r
 }
 
@@ -98,6 +180,28 @@ func (c Config) Validate() error {
 		return err
 	}
 
+	if c.Diff.PatchFileName != "" && c.Diff.GitBase != "" {
+		return ErrDiffSourceConflict
+	}
+
+	if c.ColdBlockHits < 0 {
+		return ErrColdBlockHitsNegative
+	}
+
+	if c.Ratchet.Tolerance < 0 {
+		return ErrRatchetToleranceNegative
+	}
+
+	if c.Ratchet.Update && c.Ratchet.FileName == "" {
+		return ErrRatchetFileNotSet
+	}
+
+	for i, r := range c.Reports {
+		if _, ok := reporterFactory(r.Type); !ok {
+			return fmt.Errorf("reports element[%d] %w: %s", i, ErrUnknownReportType, r.Type)
+		}
+	}
+
 	for i, pattern := range c.Exclude.Paths {
 		if err := validateRegexp(pattern); err != nil {
 			return fmt.Errorf("%w for excluded paths element[%d]: %w", ErrRegExpNotValid, i, err)
@@ -105,10 +209,15 @@ func (c Config) Validate() error {
 	}
 
 	for i, o := range c.Override {
-		if !inRange(o.Threshold) {
+		if o.Threshold != nil && !inRange(*o.Threshold) {
 			return fmt.Errorf("override element[%d] %w", i, ErrThresholdNotInRange)
 		}
 
+		if (o.MaxUncovered != nil && *o.MaxUncovered < 0) ||
+			(o.MaxUncoveredLines != nil && *o.MaxUncoveredLines < 0) {
+			return fmt.Errorf("override element[%d] %w", i, ErrMaxUncoveredNegative)
+		}
+
 		if err := validateRegexp(o.Path); err != nil {
 			return fmt.Errorf("%w for override element[%d]: %w", ErrRegExpNotValid, i, err)
 		}
@@ -122,6 +231,14 @@ func (c Config) Validate() error {
 		return fmt.Errorf("%w: %s", ErrGitOptionNotSet, err.Error())
 	}
 
+	if err := c.validatePRComment(); err != nil {
+		return fmt.Errorf("%w: %s", ErrPRCommentOptionNotSet, err.Error())
+	}
+
+	if err := c.validateCheckRun(); err != nil {
+		return fmt.Errorf("%w: %s", ErrCheckRunOptionNotSet, err.Error())
+	}
+
 	return nil
 }
 
@@ -138,6 +255,50 @@ func (c Config) validateThreshold() error {
 		return fmt.Errorf("total %w", ErrThresholdNotInRange)
 	}
 
+	if !inRange(c.Threshold.NewCode) {
+		return fmt.Errorf("new code %w", ErrThresholdNotInRange)
+	}
+
+	if !inRange(c.Threshold.Function) {
+		return fmt.Errorf("function %w", ErrThresholdNotInRange)
+	}
+
+	if err := c.MaxUncovered.validate(); err != nil {
+		return err
+	}
+
+	if err := c.MaxUncoveredLines.validate(); err != nil {
+		return fmt.Errorf("lines: %w", err)
+	}
+
+	if !inRange(c.MaxIgnored.File) {
+		return fmt.Errorf("max ignored file %w", ErrThresholdNotInRange)
+	}
+
+	if !inRange(c.MaxIgnored.Package) {
+		return fmt.Errorf("max ignored package %w", ErrThresholdNotInRange)
+	}
+
+	if !inRange(c.MaxIgnored.Total) {
+		return fmt.Errorf("max ignored total %w", ErrThresholdNotInRange)
+	}
+
+	return nil
+}
+
+func (mu MaxUncovered) validate() error {
+	if mu.File < 0 {
+		return fmt.Errorf("file %w", ErrMaxUncoveredNegative)
+	}
+
+	if mu.Package < 0 {
+		return fmt.Errorf("package %w", ErrMaxUncoveredNegative)
+	}
+
+	if mu.Total < 0 {
+		return fmt.Errorf("total %w", ErrMaxUncoveredNegative)
+	}
+
 	return nil
 }
 
@@ -159,6 +320,31 @@ func (c Config) validateGit() error {
 	return hasNonEmptyFields(c.Badge.Git)
 }
 
+func (c Config) validatePRComment() error {
+	// when pull request comment config is empty, feature is disabled and there is no need to validate
+	if reflect.DeepEqual(c.PRComment, prcomment.Github{}) {
+		return nil
+	}
+
+	pr := c.PRComment
+	pr.BaseURL = "-"   // base url is optional
+	pr.PullRequest = 1 // pull request is not set on push events, comment is then skipped
+
+	return hasNonEmptyFields(pr)
+}
+
+func (c Config) validateCheckRun() error {
+	// when check run config is empty, feature is disabled and there is no need to validate
+	if reflect.DeepEqual(c.CheckRun, checkrun.Github{}) {
+		return nil
+	}
+
+	cr := c.CheckRun
+	cr.Name, cr.BaseURL = "-", "-" // name and base url are optional
+
+	return hasNonEmptyFields(cr)
+}
+
 func hasNonEmptyFields(obj any) error {
 	v := reflect.ValueOf(obj)
 	for i := range v.NumField() {
@@ -194,4 +380,4 @@ func ConfigFromFile(cfg *Config, filename string) error {
 	return nil
 }
 
-func inRange(t int) bool { return t >= 0 && t <= 100 }
+func inRange[T int | float64](t T) bool { return t >= 0 && t <= 100 }
@@ -19,6 +19,8 @@ func Test_Config_Redacted(t *testing.T) {
 	cfg.Badge.Git.Token = nonEmptyStr
 	cfg.Badge.CDN.Secret = nonEmptyStr
 	cfg.Badge.CDN.Key = nonEmptyStr
+	cfg.PRComment.Token = nonEmptyStr
+	cfg.CheckRun.Token = nonEmptyStr
 
 	r := cfg.Redacted()
 
@@ -29,17 +31,23 @@ func Test_Config_Redacted(t *testing.T) {
 	assert.Equal(t, nonEmptyStr, cfg.Badge.Git.Token)
 	assert.Equal(t, nonEmptyStr, cfg.Badge.CDN.Secret)
 	assert.Equal(t, nonEmptyStr, cfg.Badge.CDN.Key)
+	assert.Equal(t, nonEmptyStr, cfg.PRComment.Token)
+	assert.Equal(t, nonEmptyStr, cfg.CheckRun.Token)
 
 	// redacted should have hidden values
 	assert.Equal(t, HiddenValue, r.Badge.Git.Token)
 	assert.Equal(t, HiddenValue, r.Badge.CDN.Secret)
 	assert.Equal(t, nonEmptyStr+HiddenValue, r.Badge.CDN.Key)
+	assert.Equal(t, HiddenValue, r.PRComment.Token)
+	assert.Equal(t, HiddenValue, r.CheckRun.Token)
 
 	// redacted config of empty field should not do anything
 	r = Config{}.Redacted()
 	assert.Empty(t, r.Badge.Git.Token)
 	assert.Empty(t, r.Badge.CDN.Secret)
 	assert.Empty(t, r.Badge.CDN.Key)
+	assert.Empty(t, r.PRComment.Token)
+	assert.Empty(t, r.CheckRun.Token)
 }
 
 func Test_Config_Validate(t *testing.T) {
@@ -56,6 +64,14 @@ func Test_Config_Validate(t *testing.T) {
 	cfg.Threshold.File = 101
 	assert.ErrorIs(t, cfg.Validate(), ErrThresholdNotInRange)
 
+	cfg = newValidCfg()
+	cfg.Threshold.File = 100.01
+	assert.ErrorIs(t, cfg.Validate(), ErrThresholdNotInRange)
+
+	cfg = newValidCfg()
+	cfg.Threshold.File = 87.5
+	assert.NoError(t, cfg.Validate())
+
 	cfg = newValidCfg()
 	cfg.Threshold.File = -1
 	assert.ErrorIs(t, cfg.Validate(), ErrThresholdNotInRange)
@@ -77,11 +93,76 @@ func Test_Config_Validate(t *testing.T) {
 	assert.ErrorIs(t, cfg.Validate(), ErrThresholdNotInRange)
 
 	cfg = newValidCfg()
-	cfg.Override = []Override{{Threshold: 101}}
+	cfg.Threshold.NewCode = 101
+	assert.ErrorIs(t, cfg.Validate(), ErrThresholdNotInRange)
+
+	cfg = newValidCfg()
+	cfg.Threshold.NewCode = -1
+	assert.ErrorIs(t, cfg.Validate(), ErrThresholdNotInRange)
+
+	cfg = newValidCfg()
+	cfg.Threshold.Function = 101
+	assert.ErrorIs(t, cfg.Validate(), ErrThresholdNotInRange)
+
+	cfg = newValidCfg()
+	cfg.Threshold.Function = -1
+	assert.ErrorIs(t, cfg.Validate(), ErrThresholdNotInRange)
+
+	cfg = newValidCfg()
+	cfg.MaxUncovered.File = -1
+	assert.ErrorIs(t, cfg.Validate(), ErrMaxUncoveredNegative)
+
+	cfg = newValidCfg()
+	cfg.MaxUncovered.Package = -1
+	assert.ErrorIs(t, cfg.Validate(), ErrMaxUncoveredNegative)
+
+	cfg = newValidCfg()
+	cfg.MaxUncovered.Total = -1
+	assert.ErrorIs(t, cfg.Validate(), ErrMaxUncoveredNegative)
+
+	cfg = newValidCfg()
+	cfg.MaxIgnored.File = 101
+	assert.ErrorIs(t, cfg.Validate(), ErrThresholdNotInRange)
+
+	cfg = newValidCfg()
+	cfg.MaxIgnored.Package = -1
+	assert.ErrorIs(t, cfg.Validate(), ErrThresholdNotInRange)
+
+	cfg = newValidCfg()
+	cfg.MaxIgnored.Total = 101
+	assert.ErrorIs(t, cfg.Validate(), ErrThresholdNotInRange)
+
+	cfg = newValidCfg()
+	cfg.Diff.PatchFileName = "changes.patch"
+	cfg.Diff.GitBase = "origin/main"
+	assert.ErrorIs(t, cfg.Validate(), ErrDiffSourceConflict)
+
+	cfg = newValidCfg()
+	cfg.ColdBlockHits = -1
+	assert.ErrorIs(t, cfg.Validate(), ErrColdBlockHitsNegative)
+
+	cfg = newValidCfg()
+	cfg.Ratchet.Tolerance = -0.1
+	assert.ErrorIs(t, cfg.Validate(), ErrRatchetToleranceNegative)
+
+	cfg = newValidCfg()
+	cfg.Ratchet.Update = true
+	assert.ErrorIs(t, cfg.Validate(), ErrRatchetFileNotSet)
+
+	cfg = newValidCfg()
+	cfg.Reports = []Report{{Type: ReportTypeJSON}, {Type: "unknown"}}
+	assert.ErrorIs(t, cfg.Validate(), ErrUnknownReportType)
+
+	cfg = newValidCfg()
+	cfg.Override = []Override{{Threshold: ptr(101.0)}}
 	assert.ErrorIs(t, cfg.Validate(), ErrThresholdNotInRange)
 
 	cfg = newValidCfg()
-	cfg.Override = []Override{{Threshold: 100, Path: "("}}
+	cfg.Override = []Override{{Threshold: ptr(100.0), MaxUncovered: ptr(-1)}}
+	assert.ErrorIs(t, cfg.Validate(), ErrMaxUncoveredNegative)
+
+	cfg = newValidCfg()
+	cfg.Override = []Override{{Threshold: ptr(100.0), Path: "("}}
 	assert.ErrorIs(t, cfg.Validate(), ErrRegExpNotValid)
 
 	cfg = newValidCfg()
@@ -158,6 +239,63 @@ func Test_Config_ValidateGit(t *testing.T) {
 	assert.NoError(t, cfg.Validate())
 }
 
+func Test_Config_ValidatePRComment(t *testing.T) {
+	t.Parallel()
+
+	cfg := newValidCfg()
+	cfg.PRComment.Token = nonEmptyStr
+	assert.ErrorIs(t, cfg.Validate(), ErrPRCommentOptionNotSet)
+
+	cfg = newValidCfg()
+	cfg.PRComment.Token = nonEmptyStr
+	cfg.PRComment.Owner = nonEmptyStr
+	assert.ErrorIs(t, cfg.Validate(), ErrPRCommentOptionNotSet)
+
+	// pull request is not set on push events
+	cfg = newValidCfg()
+	cfg.PRComment.Token = nonEmptyStr
+	cfg.PRComment.Owner = nonEmptyStr
+	cfg.PRComment.Repository = nonEmptyStr
+	assert.NoError(t, cfg.Validate())
+
+	// base url is optional
+	cfg = newValidCfg()
+	cfg.PRComment.Token = nonEmptyStr
+	cfg.PRComment.Owner = nonEmptyStr
+	cfg.PRComment.Repository = nonEmptyStr
+	cfg.PRComment.PullRequest = 1
+	assert.NoError(t, cfg.Validate())
+
+	cfg.PRComment.BaseURL = nonEmptyStr
+	assert.NoError(t, cfg.Validate())
+}
+
+func Test_Config_ValidateCheckRun(t *testing.T) {
+	t.Parallel()
+
+	cfg := newValidCfg()
+	cfg.CheckRun.Token = nonEmptyStr
+	assert.ErrorIs(t, cfg.Validate(), ErrCheckRunOptionNotSet)
+
+	cfg = newValidCfg()
+	cfg.CheckRun.Token = nonEmptyStr
+	cfg.CheckRun.Owner = nonEmptyStr
+	cfg.CheckRun.Repository = nonEmptyStr
+	assert.ErrorIs(t, cfg.Validate(), ErrCheckRunOptionNotSet)
+
+	// name and base url are optional
+	cfg = newValidCfg()
+	cfg.CheckRun.Token = nonEmptyStr
+	cfg.CheckRun.Owner = nonEmptyStr
+	cfg.CheckRun.Repository = nonEmptyStr
+	cfg.CheckRun.HeadSHA = nonEmptyStr
+	assert.NoError(t, cfg.Validate())
+
+	cfg.CheckRun.Name = nonEmptyStr
+	cfg.CheckRun.BaseURL = nonEmptyStr
+	assert.NoError(t, cfg.Validate())
+}
+
 func Test_ConfigFromFile(t *testing.T) {
 	t.Parallel()
 
@@ -240,19 +378,48 @@ func TestConfigYamlParse(t *testing.T) {
 
 func nonZeroConfig() Config {
 	return Config{
-		Profile:   "cover.out",
-		Threshold: Threshold{100, 100, 100},
-		Override:  []Override{{Path: "pathToFile", Threshold: 99}},
+		Profile:           "cover.out",
+		Threshold:         Threshold{File: 100, Package: 100, Total: 87.5, NewCode: 100, Function: 100},
+		MaxUncovered:      MaxUncovered{File: 5, Package: 20, Total: 100},
+		MaxUncoveredLines: MaxUncovered{File: 4, Package: 15, Total: 80},
+		MaxIgnored:        MaxIgnored{File: 20, Package: 15, Total: 10},
+		Override: []Override{{
+			Path:              "pathToFile",
+			Threshold:         ptr(99.5),
+			MaxUncovered:      ptr(3),
+			MaxUncoveredLines: ptr(2),
+		}},
 		Exclude: Exclude{
-			Paths: []string{"path1", "path2"},
+			Paths:     []string{"path1", "path2"},
+			Generated: true,
 		},
 		BreakdownFileName: "breakdown.testcoverage",
 		Diff: Diff{
 			BaseBreakdownFileName: "breakdown.testcoverage",
 			Threshold:             ptr(-1.01),
+			PatchFileName:         "changes.patch",
+			GitBase:               "origin/main",
+		},
+		Ratchet: Ratchet{
+			FileName:  "ratchet.testcoverage",
+			Tolerance: 0.5,
 		},
 		GithubActionOutput:     true,
+		GitlabOutput:           true,
 		ForceAnnotationComment: false,
+		ForceAnnotationIssue:   true,
+		ColdBlockHits:          3,
+		HTMLReport:             "report.html",
+		CoberturaFile:          "cobertura.xml",
+		LcovFile:               "lcov.info",
+		JSONReport:             "report.json",
+		SarifFile:              "report.sarif",
+		SarifUncoveredLines:    true,
+		JUnitFile:              "junit.xml",
+		Reports: []Report{
+			{Type: "human", Disabled: true},
+			{Type: "json", File: "coverage.json"},
+		},
 	}
 }
 
@@ -262,20 +429,57 @@ profile: cover.out
 threshold:
     file: 100
     package: 100
+    total: 87.5
+    new-code: 100
+    function: 100
+max-uncovered:
+    file: 5
+    package: 20
     total: 100
+max-uncovered-lines:
+    file: 4
+    package: 15
+    total: 80
+max-ignored:
+    file: 20
+    package: 15
+    total: 10
 override:
-    - threshold: 99
+    - threshold: 99.5
+      max-uncovered: 3
+      max-uncovered-lines: 2
       path: pathToFile
 force-annotation-comment: false
+force-annotation-issue: true
 exclude:
   paths:
     - path1
     - path2
+  generated: true
 breakdown-file-name: 'breakdown.testcoverage'
 diff:
   base-breakdown-file-name: 'breakdown.testcoverage'
   threshold: -1.01
-github-action-output: true`
+  patch-file-name: 'changes.patch'
+  git-base: 'origin/main'
+ratchet:
+  file-name: 'ratchet.testcoverage'
+  tolerance: 0.5
+github-action-output: true
+gitlab-output: true
+cold-block-hits: 3
+html-report: 'report.html'
+cobertura-file: 'cobertura.xml'
+lcov-file: 'lcov.info'
+json-report: 'report.json'
+sarif-file: 'report.sarif'
+sarif-uncovered-lines: true
+junit-file: 'junit.xml'
+reports:
+  - type: human
+    disabled: true
+  - type: json
+    file: 'coverage.json'`
 }
 
 func newValidCfg() Config {
@@ -0,0 +1,138 @@
+package coverage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// BreakdownVersion is the version of breakdown format written by the tool.
+// Version 1 is legacy format, which holds only `name;total;covered` lines.
+const BreakdownVersion = 2
+
+var ErrUnsupportedVersion = errors.New("unsupported breakdown version")
+
+type Breakdown struct {
+	Header BreakdownHeader
+	Stats  []Stats
+}
+
+type BreakdownHeader struct {
+	Version     int
+	ToolVersion string
+	Module      string
+	Commit      string
+	Timestamp   time.Time
+}
+
+type breakdownJSON struct {
+	Header breakdownHeaderJSON `json:"header"`
+	Stats  []statsJSON         `json:"stats"`
+}
+
+type breakdownHeaderJSON struct {
+	Version     int       `json:"version"`
+	ToolVersion string    `json:"tool-version,omitempty"`
+	Module      string    `json:"module,omitempty"`
+	Commit      string    `json:"commit,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+type statsJSON struct {
+	Name                       string     `json:"name"`
+	Total                      int64      `json:"total"`
+	Covered                    int64      `json:"covered"`
+	UncoveredLines             []int      `json:"uncovered-lines,omitempty"`
+	Annotations                []int      `json:"annotations,omitempty"`
+	AnnotationsWithoutComments []int      `json:"annotations-without-comments,omitempty"`
+	Functions                  []funcJSON `json:"functions,omitempty"`
+}
+
+type funcJSON struct {
+	Name      string `json:"name"`
+	StartLine int    `json:"start-line"`
+	EndLine   int    `json:"end-line"`
+	Total     int64  `json:"total"`
+	Covered   int64  `json:"covered"`
+}
+
+// BreakdownSerialize serializes breakdown in versioned (JSON) format.
+func BreakdownSerialize(b Breakdown) ([]byte, error) {
+	bj := breakdownJSON{
+		Header: breakdownHeaderJSON(b.Header),
+		Stats:  make([]statsJSON, len(b.Stats)),
+	}
+	bj.Header.Version = BreakdownVersion
+
+	for i, s := range b.Stats {
+		funcs := make([]funcJSON, len(s.Functions))
+		for j, f := range s.Functions {
+			funcs[j] = funcJSON(f)
+		}
+
+		bj.Stats[i] = statsJSON{
+			Name:                       s.Name,
+			Total:                      s.Total,
+			Covered:                    s.Covered,
+			UncoveredLines:             s.UncoveredLines,
+			Annotations:                s.Annotations,
+			AnnotationsWithoutComments: s.AnnotationsWithoutComments,
+			Functions:                  funcs,
+		}
+	}
+
+	data, err := json.MarshalIndent(bj, "", "  ")
+	if err != nil { // coverage-ignore
+		return nil, fmt.Errorf("marshal breakdown: %w", err)
+	}
+
+	return append(data, '\n'), nil
+}
+
+// BreakdownDeserialize deserializes breakdown from data in versioned (JSON) format
+// or in legacy format. Breakdown in legacy format has header with version 1.
+func BreakdownDeserialize(data []byte) (Breakdown, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		stats, err := statsDeserializeLegacy(data)
+		if err != nil {
+			return Breakdown{}, err
+		}
+
+		return Breakdown{Header: BreakdownHeader{Version: 1}, Stats: stats}, nil
+	}
+
+	var bj breakdownJSON
+	if err := json.Unmarshal(data, &bj); err != nil {
+		return Breakdown{}, fmt.Errorf("%w: %w", ErrInvalidFormat, err)
+	}
+
+	if bj.Header.Version < 2 || bj.Header.Version > BreakdownVersion { //nolint:mnd // relax
+		return Breakdown{}, fmt.Errorf("%w: %d", ErrUnsupportedVersion, bj.Header.Version)
+	}
+
+	b := Breakdown{
+		Header: BreakdownHeader(bj.Header),
+		Stats:  make([]Stats, len(bj.Stats)),
+	}
+
+	for i, s := range bj.Stats {
+		var funcs []FuncStats
+		for _, f := range s.Functions {
+			funcs = append(funcs, FuncStats(f))
+		}
+
+		b.Stats[i] = Stats{
+			Name:                       s.Name,
+			Total:                      s.Total,
+			Covered:                    s.Covered,
+			UncoveredLines:             s.UncoveredLines,
+			Annotations:                s.Annotations,
+			AnnotationsWithoutComments: s.AnnotationsWithoutComments,
+			Functions:                  funcs,
+		}
+	}
+
+	return b, nil
+}
@@ -0,0 +1,69 @@
+package coverage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/coverage"
+)
+
+func TestBreakdownSerialization(t *testing.T) {
+	t.Parallel()
+
+	b := Breakdown{
+		Header: BreakdownHeader{
+			ToolVersion: "v2.0.0",
+			Module:      "github.com/foo/bar",
+			Commit:      "abc123",
+			Timestamp:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		Stats: []Stats{
+			{
+				Name:           "foo.go",
+				Total:          11,
+				Covered:        8,
+				UncoveredLines: []int{3, 4, 10},
+				Annotations:    []int{7},
+				Functions: []FuncStats{
+					{Name: "Foo", StartLine: 1, EndLine: 5, Total: 4, Covered: 2},
+				},
+			},
+			{Name: "bar.go", Total: 9, Covered: 9},
+		},
+	}
+
+	data, err := BreakdownSerialize(b)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"uncovered-lines"`)
+
+	ds, err := BreakdownDeserialize(data)
+	assert.NoError(t, err)
+
+	b.Header.Version = BreakdownVersion
+	assert.Equal(t, b, ds)
+
+	// stats are deserialized from versioned format as well
+	stats, err := StatsDeserialize(data)
+	assert.NoError(t, err)
+	assert.Equal(t, b.Stats, stats)
+
+	// legacy format
+	ds, err = BreakdownDeserialize([]byte("foo;11;1\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, Breakdown{
+		Header: BreakdownHeader{Version: 1},
+		Stats:  []Stats{{Name: "foo", Total: 11, Covered: 1}},
+	}, ds)
+
+	// invalid formats
+	_, err = BreakdownDeserialize([]byte("foo;;11"))
+	assert.ErrorIs(t, err, ErrInvalidFormat)
+
+	_, err = BreakdownDeserialize([]byte("{"))
+	assert.ErrorIs(t, err, ErrInvalidFormat)
+
+	_, err = BreakdownDeserialize([]byte(`{"header":{"version":99}}`))
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+}
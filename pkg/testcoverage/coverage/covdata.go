@@ -0,0 +1,517 @@
+package coverage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/cover"
+)
+
+// This file implements decoding of binary coverage data files, which are written
+// to GOCOVERDIR by binaries built with `go build -cover`. Decoding follows format
+// defined in Go's internal/coverage package (meta-data and counter file version 1).
+
+var ErrInvalidCovData = errors.New("invalid coverage data")
+
+const (
+	covMetaFilePrefix    = "covmeta"
+	covCounterFilePrefix = "covcounters"
+
+	covMetaFileVersion    = 1
+	covCounterFileVersion = 1
+
+	covMetaSymbolHeaderSize = 44
+
+	ctrModeSet           = 1
+	ctrModeCount         = 2
+	ctrModeAtomic        = 3
+	ctrGranularityPerFun = 2
+
+	ctrFlavorRaw     = 1
+	ctrFlavorULeb128 = 2
+)
+
+//nolint:gochecknoglobals // relax
+var (
+	covMetaMagic    = [4]byte{0x00, 0x63, 0x76, 0x6d}
+	covCounterMagic = [4]byte{0x00, 0x63, 0x77, 0x6d}
+)
+
+type covMetaFileHeader struct {
+	Magic        [4]byte
+	Version      uint32
+	TotalLength  uint64
+	Entries      uint64
+	MetaFileHash [16]byte
+	StrTabOffset uint32
+	StrTabLength uint32
+	CMode        uint8
+	CGranularity uint8
+	_            [6]byte
+}
+
+type covMetaSymbolHeader struct {
+	Length     uint32
+	PkgName    uint32
+	PkgPath    uint32
+	ModulePath uint32
+	MetaHash   [16]byte
+	_          [4]byte
+	NumFiles   uint32
+	NumFuncs   uint32
+}
+
+type covCounterFileHeader struct {
+	Magic     [4]byte
+	Version   uint32
+	MetaHash  [16]byte
+	CFlavor   uint8
+	BigEndian bool
+	_         [6]byte
+}
+
+type covCounterSegmentHeader struct {
+	FcnEntries uint64
+	StrTabLen  uint32
+	ArgsLen    uint32
+}
+
+type covCounterFileFooter struct {
+	Magic       [4]byte
+	_           [4]byte
+	NumSegments uint32
+	_           [4]byte
+}
+
+// covMetaFile holds decoded meta-data file, where functions are indexed
+// by package index and function index within package.
+type covMetaFile struct {
+	mode        uint8
+	granularity uint8
+	funcs       [][]covFunc
+}
+
+type covFunc struct {
+	file   string
+	blocks []cover.ProfileBlock
+}
+
+// isCovDataDir returns true if path is directory, which is expected
+// to hold binary coverage data.
+func isCovDataDir(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.IsDir()
+}
+
+// parseCovDataDir decodes binary coverage data files from dir and returns
+// profiles in the same form as they would be read from text profile.
+func parseCovDataDir(dir string) ([]*cover.Profile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil { // coverage-ignore
+		return nil, fmt.Errorf("reading coverage dir: %w", err)
+	}
+
+	metaFiles := make(map[[16]byte]*covMetaFile)
+	metaFilesOrder := make([][16]byte, 0)
+
+	var counterFiles []string
+
+	for _, e := range entries {
+		name := filepath.Join(dir, e.Name())
+
+		switch {
+		case strings.HasPrefix(e.Name(), covMetaFilePrefix+"."):
+			hash, mf, err := readCovMetaFile(name)
+			if err != nil {
+				return nil, fmt.Errorf("reading meta-data file %q: %w", name, err)
+			}
+
+			metaFiles[hash] = mf
+			metaFilesOrder = append(metaFilesOrder, hash)
+		case strings.HasPrefix(e.Name(), covCounterFilePrefix+"."):
+			counterFiles = append(counterFiles, name)
+		}
+	}
+
+	if len(metaFiles) == 0 {
+		return nil, fmt.Errorf("%w: no meta-data files found in %q", ErrInvalidCovData, dir)
+	}
+
+	for _, name := range counterFiles {
+		if err := readCovCounterFile(name, metaFiles); err != nil {
+			return nil, fmt.Errorf("reading counter file %q: %w", name, err)
+		}
+	}
+
+	var result []*cover.Profile
+
+	for _, hash := range metaFilesOrder {
+		profiles := metaFiles[hash].profiles()
+
+		if result == nil {
+			result = profiles
+			continue
+		}
+
+		result, err = mergeProfiles(result, profiles)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (mf *covMetaFile) profiles() []*cover.Profile {
+	mode := "set"
+	if mf.mode == ctrModeCount {
+		mode = "count"
+	} else if mf.mode == ctrModeAtomic {
+		mode = "atomic"
+	}
+
+	var result []*cover.Profile
+
+	byFile := make(map[string]*cover.Profile)
+
+	for _, pkg := range mf.funcs {
+		for _, fn := range pkg {
+			p, ok := byFile[fn.file]
+			if !ok {
+				p = &cover.Profile{FileName: fn.file, Mode: mode}
+				byFile[fn.file] = p
+				result = append(result, p)
+			}
+
+			p.Blocks = append(p.Blocks, fn.blocks...)
+		}
+	}
+
+	for _, p := range result {
+		slices.SortFunc(p.Blocks, func(a, b cover.ProfileBlock) int {
+			if a.StartLine != b.StartLine {
+				return a.StartLine - b.StartLine
+			}
+
+			return a.StartCol - b.StartCol
+		})
+	}
+
+	return result
+}
+
+func (mf *covMetaFile) addCounters(pkgIdx, funcIdx uint32, counters []uint32) error {
+	if int(pkgIdx) >= len(mf.funcs) || int(funcIdx) >= len(mf.funcs[pkgIdx]) {
+		return fmt.Errorf("%w: counters for unknown function", ErrInvalidCovData)
+	}
+
+	fn := mf.funcs[pkgIdx][funcIdx]
+
+	for i := range fn.blocks {
+		var c uint32
+
+		switch {
+		case mf.granularity == ctrGranularityPerFun && len(counters) > 0:
+			c = counters[0]
+		case i < len(counters):
+			c = counters[i]
+		}
+
+		if mf.mode == ctrModeSet {
+			fn.blocks[i].Count = max(fn.blocks[i].Count, min(int(c), 1))
+		} else {
+			fn.blocks[i].Count += int(c)
+		}
+	}
+
+	return nil
+}
+
+func readCovMetaFile(name string) ([16]byte, *covMetaFile, error) {
+	data, err := os.ReadFile(name)
+	if err != nil { // coverage-ignore
+		return [16]byte{}, nil, err //nolint:wrapcheck // error is wrapped at level above
+	}
+
+	var hdr covMetaFileHeader
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &hdr); err != nil {
+		return [16]byte{}, nil, fmt.Errorf("%w: %w", ErrInvalidCovData, err)
+	}
+
+	if hdr.Magic != covMetaMagic || hdr.Version > covMetaFileVersion {
+		return [16]byte{}, nil, fmt.Errorf("%w: unsupported meta-data file", ErrInvalidCovData)
+	}
+
+	r := &covReader{b: data, off: binary.Size(hdr)}
+
+	offsets := make([]uint64, hdr.Entries)
+	for i := range offsets {
+		offsets[i] = r.uint64()
+	}
+
+	lengths := make([]uint64, hdr.Entries)
+	for i := range lengths {
+		lengths[i] = r.uint64()
+	}
+
+	if r.err != nil {
+		return [16]byte{}, nil, r.err
+	}
+
+	mf := &covMetaFile{
+		mode:        hdr.CMode,
+		granularity: hdr.CGranularity,
+		funcs:       make([][]covFunc, hdr.Entries),
+	}
+
+	for i := range offsets {
+		end := offsets[i] + lengths[i]
+		if end < offsets[i] || end > uint64(len(data)) {
+			return [16]byte{}, nil, fmt.Errorf("%w: invalid package offset", ErrInvalidCovData)
+		}
+
+		mf.funcs[i], err = readCovMetaPackage(data[offsets[i]:end])
+		if err != nil {
+			return [16]byte{}, nil, err
+		}
+	}
+
+	return hdr.MetaFileHash, mf, nil
+}
+
+func readCovMetaPackage(data []byte) ([]covFunc, error) {
+	var hdr covMetaSymbolHeader
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &hdr); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCovData, err)
+	}
+
+	r := &covReader{b: data, off: covMetaSymbolHeaderSize}
+
+	funcOffsets := make([]uint32, hdr.NumFuncs)
+	for i := range funcOffsets {
+		funcOffsets[i] = r.uint32(binary.LittleEndian)
+	}
+
+	strtab := r.stringTable()
+
+	funcs := make([]covFunc, hdr.NumFuncs)
+
+	for i, off := range funcOffsets {
+		r.off = int(off)
+
+		numUnits := r.uleb128()
+		r.uleb128() // function name
+		file := strtab.get(r.uleb128())
+
+		if r.err != nil {
+			return nil, r.err
+		}
+
+		fn := covFunc{file: file, blocks: make([]cover.ProfileBlock, 0, min(numUnits, uint64(len(data))))}
+
+		for range numUnits {
+			fn.blocks = append(fn.blocks, cover.ProfileBlock{
+				StartLine: int(r.uleb128()),
+				StartCol:  int(r.uleb128()),
+				EndLine:   int(r.uleb128()),
+				EndCol:    int(r.uleb128()),
+				NumStmt:   int(r.uleb128()),
+			})
+		}
+
+		funcs[i] = fn
+	}
+
+	if r.err == nil && strtab.err != nil {
+		r.err = strtab.err
+	}
+
+	return funcs, r.err
+}
+
+func readCovCounterFile(name string, metaFiles map[[16]byte]*covMetaFile) error {
+	data, err := os.ReadFile(name)
+	if err != nil { // coverage-ignore
+		return err //nolint:wrapcheck // error is wrapped at level above
+	}
+
+	var (
+		hdr covCounterFileHeader
+		ftr covCounterFileFooter
+	)
+
+	hdrSize, ftrSize := binary.Size(hdr), binary.Size(ftr)
+	if len(data) < hdrSize+ftrSize {
+		return fmt.Errorf("%w: counter file too short", ErrInvalidCovData)
+	}
+
+	//nolint:errcheck // data length is checked above
+	binary.Read(bytes.NewReader(data), binary.LittleEndian, &hdr)
+	//nolint:errcheck // data length is checked above
+	binary.Read(bytes.NewReader(data[len(data)-ftrSize:]), binary.LittleEndian, &ftr)
+
+	if hdr.Magic != covCounterMagic || ftr.Magic != covCounterMagic ||
+		hdr.Version > covCounterFileVersion {
+		return fmt.Errorf("%w: unsupported counter file", ErrInvalidCovData)
+	}
+
+	mf, found := metaFiles[hdr.MetaHash]
+	if !found {
+		return fmt.Errorf("%w: meta-data file not found", ErrInvalidCovData)
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+	if hdr.BigEndian {
+		order = binary.BigEndian
+	}
+
+	readCounter := func(r *covReader) uint32 {
+		if hdr.CFlavor == ctrFlavorULeb128 {
+			return uint32(r.uleb128()) //nolint:gosec // counters are uint32
+		}
+
+		return r.uint32(order)
+	}
+
+	r := &covReader{b: data, off: hdrSize}
+
+	for range ftr.NumSegments {
+		var shdr covCounterSegmentHeader
+
+		shdr.FcnEntries = r.uint64()
+		shdr.StrTabLen = r.uint32(binary.LittleEndian)
+		shdr.ArgsLen = r.uint32(binary.LittleEndian)
+
+		// string table and arguments are not needed, followed by padding to 4 bytes
+		r.off += int(shdr.StrTabLen) + int(shdr.ArgsLen)
+		r.off = (r.off + 3) &^ 3 //nolint:mnd // relax
+
+		if hdr.CFlavor != ctrFlavorRaw && hdr.CFlavor != ctrFlavorULeb128 {
+			return fmt.Errorf("%w: unknown counter flavor", ErrInvalidCovData)
+		}
+
+		for range shdr.FcnEntries {
+			numCounters := readCounter(r)
+			pkgIdx := readCounter(r)
+			funcIdx := readCounter(r)
+
+			if r.err != nil {
+				return r.err
+			}
+
+			counters := make([]uint32, 0, min(int(numCounters), len(data)))
+			for range numCounters {
+				counters = append(counters, readCounter(r))
+			}
+
+			if r.err != nil {
+				return r.err
+			}
+
+			if err := mf.addCounters(pkgIdx, funcIdx, counters); err != nil {
+				return err
+			}
+		}
+
+		r.off += ftrSize // every segment is followed by footer
+	}
+
+	return r.err
+}
+
+// covReader reads values from byte slice. Once reading fails, error is
+// retained and all subsequent reads return zero values.
+type covReader struct {
+	b   []byte
+	off int
+	err error
+}
+
+func (r *covReader) next(n int) []byte {
+	if r.err != nil || r.off < 0 || n > len(r.b)-r.off {
+		r.err = fmt.Errorf("%w: unexpected end of data", ErrInvalidCovData)
+		return nil
+	}
+
+	b := r.b[r.off : r.off+n]
+	r.off += n
+
+	return b
+}
+
+func (r *covReader) uint32(order binary.ByteOrder) uint32 {
+	if b := r.next(4); b != nil { //nolint:mnd // relax
+		return order.Uint32(b)
+	}
+
+	return 0
+}
+
+func (r *covReader) uint64() uint64 {
+	if b := r.next(8); b != nil { //nolint:mnd // relax
+		return binary.LittleEndian.Uint64(b)
+	}
+
+	return 0
+}
+
+func (r *covReader) uleb128() uint64 {
+	var (
+		value uint64
+		shift uint
+	)
+
+	for {
+		b := r.next(1)
+		if b == nil {
+			return 0
+		}
+
+		value |= uint64(b[0]&0x7F) << shift //nolint:mnd // relax
+		if b[0]&0x80 == 0 {
+			return value
+		}
+
+		shift += 7
+	}
+}
+
+func (r *covReader) stringTable() *covStringTable {
+	n := r.uleb128()
+	strs := make([]string, 0, min(n, uint64(len(r.b))))
+
+	for range n {
+		l := r.uleb128()
+		if l > uint64(len(r.b)) {
+			r.err = fmt.Errorf("%w: invalid string length", ErrInvalidCovData)
+		}
+
+		if r.err != nil {
+			break
+		}
+
+		strs = append(strs, string(r.next(int(l))))
+	}
+
+	return &covStringTable{strs: strs}
+}
+
+type covStringTable struct {
+	strs []string
+	err  error
+}
+
+func (t *covStringTable) get(idx uint64) string {
+	if idx >= uint64(len(t.strs)) {
+		t.err = fmt.Errorf("%w: invalid string table index", ErrInvalidCovData)
+		return ""
+	}
+
+	return t.strs[idx]
+}
@@ -6,11 +6,14 @@ import (
 	"go/build"
 	"go/parser"
 	"go/token"
+	"maps"
 	"math"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"golang.org/x/tools/cover"
 
@@ -18,34 +21,66 @@ import (
 	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/path"
 )
 
-const IgnoreText = "coverage-ignore"
+const (
+	IgnoreText      = "coverage-ignore"
+	IgnoreStartText = "coverage-ignore-start"
+	IgnoreEndText   = "coverage-ignore-end"
+
+	IgnoreFileText    = "coverage-ignore-file"
+	IgnorePackageText = "coverage-ignore-package"
+
+	// ExcludedGenerated marks exclusion of generated file.
+	ExcludedGenerated = "generated"
+)
 
 type Config struct {
 	Profiles               []string
 	ExcludePaths           []string
 	SourceDir              string
 	ForceAnnotationComment bool
+	ForceAnnotationIssue   bool
+	ExcludeGenerated       bool
+}
+
+// Exclusion is file excluded from coverage statistics by its source.
+type Exclusion struct {
+	Name   string
+	By     string // IgnoreFileText, IgnorePackageText or ExcludedGenerated
+	Reason string
 }
 
 func GenerateCoverageStats(cfg Config) ([]Stats, error) {
+	stats, _, err := GenerateCoverage(cfg)
+	return stats, err
+}
+
+// GenerateCoverage returns coverage statistics of files, and files which are
+// excluded from statistics by their source; either with directives (coverage-ignore-file
+// or coverage-ignore-package), or as generated files.
+//
+//nolint:cyclop // relax
+func GenerateCoverage(cfg Config) ([]Stats, []Exclusion, error) {
 	profiles, err := parseProfiles(cfg.Profiles)
 	if err != nil {
-		return nil, fmt.Errorf("parsing profiles: %w", err)
+		return nil, nil, fmt.Errorf("parsing profiles: %w", err)
 	}
 
 	files, err := findFiles(profiles, cfg.SourceDir)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	fileStats := make([]Stats, 0, len(profiles))
 	excludeRules := compileExcludePathRules(cfg.ExcludePaths)
+	findPackageDirective := packageDirectiveCreator()
+
+	var exclusions []Exclusion
 
 	for _, profile := range profiles {
 		fi, ok := files[profile.FileName]
 		if !ok { // coverage-ignore
 			// should already be handled above, but let's check it again
-			return nil, fmt.Errorf("could not find file [%s]", profile.FileName)
+			return nil, nil, fmt.Errorf("could not find file [%s]", profile.FileName)
 		}
 
 		if ok := matches(excludeRules, fi.name); ok {
@@ -53,18 +88,25 @@ func GenerateCoverageStats(cfg Config) ([]Stats, error) {
 			continue // this file is excluded
 		}
 
-		s, err := coverageForFile(profile, fi, cfg.ForceAnnotationComment)
+		s, exclusion, err := coverageForFile(profile, fi, cfg, findPackageDirective)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
-		if s.Total == 0 {
+		if exclusion != nil {
+			logger.L.Debug().Str("file", fi.name).Str("excluded", exclusion.By).Msg("file excluded")
+			exclusions = append(exclusions, *exclusion)
+
+			continue
+		}
+
+		if s.Total == 0 && s.Ignored == 0 {
 			// do not include files that doesn't have statements.
-			// this can happen when everything is excluded with comment annotations, or
-			// simply file doesn't have any statement.
+			// files where every statement is ignored with comment annotations are still
+			// included, so that ignored statements are accounted for.
 			//
 			// note: we are explicitly adding `continue` statement, instead of having code like this:
-			// if s.Total != 0 {
+			// if s.Total != 0 || s.Ignored != 0 {
 			// 	fileStats = append(fileStats, s)
 			// }
 			// because with `continue` add additional statements in coverage profile which will require
@@ -76,28 +118,156 @@ func GenerateCoverageStats(cfg Config) ([]Stats, error) {
 		fileStats = append(fileStats, s)
 	}
 
-	return fileStats, nil
+	return fileStats, exclusions, nil
 }
 
-func coverageForFile(profile *cover.Profile, fi fileInfo, forceComment bool) (Stats, error) {
+// coverageForFile returns coverage statistics of file, or exclusion when file is
+// generated (and generated files are excluded) or excluded with directive in its
+// header or in header of package's doc.go file.
+func coverageForFile(
+	profile *cover.Profile,
+	fi fileInfo,
+	cfg Config,
+	findPackageDirective func(dir string) (string, bool),
+) (Stats, *Exclusion, error) {
 	source, err := os.ReadFile(fi.path)
 	if err != nil { // coverage-ignore
-		return Stats{}, fmt.Errorf("failed reading file source [%s]: %w", fi.path, err)
+		return Stats{}, nil, fmt.Errorf("failed reading file source [%s]: %w", fi.path, err)
 	}
 
 	fset, node, err := parseSource(source)
 	if err != nil { // coverage-ignore
-		return Stats{}, err
+		return Stats{}, nil, err
+	}
+
+	if cfg.ExcludeGenerated && ast.IsGenerated(node) {
+		reason := generatedComment(node)
+		return Stats{}, &Exclusion{Name: fi.name, By: ExcludedGenerated, Reason: reason}, nil
+	}
+
+	if reason, ok := directiveFromAST(node, IgnoreFileText); ok {
+		return Stats{}, &Exclusion{Name: fi.name, By: IgnoreFileText, Reason: reason}, nil
+	}
+
+	if reason, ok := findPackageDirective(filepath.Dir(fi.path)); ok {
+		return Stats{}, &Exclusion{Name: fi.name, By: IgnorePackageText, Reason: reason}, nil
 	}
 
 	funcs, blocks := funcsAndBlocksFromAST(fset, node)
-	annotations, withoutComment := annotationsFromAST(fset, node, forceComment)
+	opts := annotationOptions{
+		forceComment: cfg.ForceAnnotationComment,
+		forceIssue:   cfg.ForceAnnotationIssue,
+		now:          time.Now(),
+	}
+	annotations := annotationsFromAST(fset, node, opts)
+	ranges := rangeAnnotationsFromAST(fset, node, opts)
 
-	s := sumCoverage(profile, funcs, blocks, annotations)
+	s := sumCoverage(profile, funcs, blocks, annotations.valid, ranges.valid)
 	s.Name = fi.name
-	s.AnnotationsWithoutComments = pluckStartLine(withoutComment)
+	s.Path = fi.path
+	s.Functions = nameFunctions(s.Functions, funcNamesFromAST(fset, node))
+	s.Annotations = dedup(append(pluckStartLine(annotations.valid), pluckStartLine(ranges.valid)...))
+	s.AnnotationsWithoutComments = dedup(append(
+		pluckStartLine(annotations.withoutComment), pluckStartLine(ranges.withoutComment)...,
+	))
+	s.UnmatchedAnnotations = dedup(append(
+		pluckStartLine(annotations.unmatched), pluckStartLine(ranges.unmatched)...,
+	))
+	s.ExpiredAnnotations = dedup(append(
+		pluckStartLine(annotations.expired), pluckStartLine(ranges.expired)...,
+	))
+	s.InvalidAnnotations = dedup(append(
+		pluckStartLine(annotations.invalid), pluckStartLine(ranges.invalid)...,
+	))
+	s.AnnotationsWithoutIssue = dedup(append(
+		pluckStartLine(annotations.withoutIssue), pluckStartLine(ranges.withoutIssue)...,
+	))
+
+	return s, nil, nil
+}
 
-	return s, nil
+// packageDirectiveCreator returns function which finds coverage-ignore-package
+// directive of package dir. Result is cached per dir, as all files of package
+// share the same doc.go file.
+func packageDirectiveCreator() func(dir string) (string, bool) {
+	type directive struct {
+		reason string
+		found  bool
+	}
+
+	cache := make(map[string]directive)
+
+	return func(dir string) (string, bool) {
+		d, exists := cache[dir]
+		if !exists {
+			d.reason, d.found = packageDirective(dir)
+			cache[dir] = d
+		}
+
+		return d.reason, d.found
+	}
+}
+
+// packageDirective returns reason of coverage-ignore-package directive
+// found in header of doc.go file in package dir.
+func packageDirective(dir string) (string, bool) {
+	source, err := os.ReadFile(filepath.Join(dir, "doc.go"))
+	if err != nil {
+		return "", false // package does not have doc.go file
+	}
+
+	_, node, err := parseSource(source)
+	if err != nil {
+		return "", false
+	}
+
+	return directiveFromAST(node, IgnorePackageText)
+}
+
+// directiveFromAST returns reason of directive found in file header, which
+// are comments before package clause.
+func directiveFromAST(node *ast.File, directive string) (string, bool) {
+	for _, cg := range node.Comments {
+		if cg.Pos() > node.Package {
+			break
+		}
+
+		for _, c := range cg.List {
+			if reason, ok := strings.CutPrefix(commentText(c), directive); ok {
+				return strings.TrimLeft(strings.TrimSpace(reason), "-: "), true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// generatedComment returns `Code generated ... DO NOT EDIT.` comment
+// from header of generated file.
+func generatedComment(node *ast.File) string {
+	for _, cg := range node.Comments {
+		if cg.Pos() > node.Package {
+			break
+		}
+
+		for _, c := range cg.List {
+			if text := commentText(c); strings.HasPrefix(text, "Code generated ") {
+				return text
+			}
+		}
+	}
+
+	return ""
+}
+
+// commentText returns text of comment without comment markers.
+func commentText(c *ast.Comment) string {
+	text := strings.TrimPrefix(c.Text, "//")
+	if strings.HasPrefix(text, "/*") {
+		text = strings.TrimSuffix(strings.TrimPrefix(text, "/*"), "*/")
+	}
+
+	return strings.TrimSpace(text)
 }
 
 type fileInfo struct {
@@ -278,31 +448,269 @@ func findAnnotations(source []byte, forceComment bool) ([]extent, []extent, erro
 		return nil, nil, err
 	}
 
-	a, w := annotationsFromAST(fset, node, forceComment)
+	a := annotationsFromAST(fset, node, annotationOptions{forceComment: forceComment, now: time.Now()})
+
+	return a.valid, a.withoutComment, nil
+}
+
+type annotationOptions struct {
+	forceComment bool
+	forceIssue   bool
+	now          time.Time
+}
+
+type annotations struct {
+	valid          []extent
+	withoutComment []extent
+	withoutIssue   []extent
+	expired        []extent
+	invalid        []extent // annotations with `until` date which can not be parsed
+	unmatched      []extent // range annotations without pair and misplaced directives
+}
+
+func annotationsFromAST(fset *token.FileSet, node *ast.File, opts annotationOptions) annotations {
+	var res annotations
+
+	for _, cg := range node.Comments {
+		for i, c := range cg.List {
+			if !strings.Contains(c.Text, IgnoreText) {
+				continue // does not have annotation continue to next comment
+			}
+
+			if isRangeAnnotation(c) {
+				continue // range annotations are handled by rangeAnnotationsFromAST
+			}
+
+			ag := annotationGroup(cg, i)
+			e := newExtent(fset, ag)
+
+			if isDirective(c) {
+				// file and package directives are only valid in file header, and files
+				// excluded with them are not analyzed. directive found here is misplaced
+				// and it is reported instead of being used as line annotation.
+				res.unmatched = append(res.unmatched, e)
+				continue
+			}
+
+			meta := parseAnnotationMeta(ag.Text(), IgnoreText)
+
+			if meta.invalid() {
+				res.invalid = append(res.invalid, e) // invalid annotation does not exclude code
+				continue
+			}
+
+			if meta.expired(opts.now) {
+				res.expired = append(res.expired, e) // expired annotation does not exclude code
+				continue
+			}
+
+			res.valid = append(res.valid, e)
+
+			if opts.forceComment && !hasComment(meta.comment) {
+				res.withoutComment = append(res.withoutComment, e)
+			}
+
+			if opts.forceIssue && meta.issue == "" {
+				res.withoutIssue = append(res.withoutIssue, e)
+			}
+		}
+	}
+
+	return res
+}
+
+// annotationGroup returns comment at index i of comment group, together with
+// comments following it which can hold its explanation, up to the next comment
+// with annotation.
+func annotationGroup(cg *ast.CommentGroup, i int) *ast.CommentGroup {
+	end := i + 1
+	for end < len(cg.List) && !strings.Contains(cg.List[end].Text, IgnoreText) {
+		end++
+	}
+
+	return &ast.CommentGroup{List: cg.List[i:end]}
+}
+
+// annotationMeta holds metadata of annotation, which is set with `key=value`
+// fields right after annotation, e.g. `until=2027-01-01 issue=PROJ-123: reason`.
+type annotationMeta struct {
+	until   string // date in YYYY-MM-DD format after which annotation expires
+	issue   string // reference to issue tracking ignored code
+	comment string // annotation text without metadata fields
+}
+
+func parseAnnotationMeta(text, annotation string) annotationMeta {
+	meta := annotationMeta{comment: text}
+
+	_, after, _ := strings.Cut(text, annotation)
+	for _, field := range strings.Fields(after) {
+		key, value, _ := strings.Cut(field, "=")
+		value = strings.TrimRight(value, ":,")
+
+		switch key {
+		case "until":
+			meta.until = value
+		case "issue":
+			meta.issue = value
+		default:
+			return meta // metadata fields are only expected before explanation
+		}
+
+		meta.comment = strings.Replace(meta.comment, field, "", 1)
+	}
+
+	return meta
+}
+
+// invalid returns true when annotation has expiry date which is not in YYYY-MM-DD format.
+func (m annotationMeta) invalid() bool {
+	if m.until == "" {
+		return false
+	}
+
+	_, err := time.Parse(time.DateOnly, m.until)
+
+	return err != nil
+}
+
+// expired returns true when annotation has valid expiry date which has passed.
+func (m annotationMeta) expired(now time.Time) bool {
+	if m.until == "" {
+		return false
+	}
+
+	until, err := time.Parse(time.DateOnly, m.until)
+	if err != nil {
+		return false
+	}
 
-	return a, w, nil
+	return !now.Before(until.AddDate(0, 0, 1)) // annotation is valid through the whole day
 }
 
-func annotationsFromAST(
+// findRangeAnnotations finds coverage-ignore-start/end annotations, checks for
+// explanations and finds annotations without matching pair.
+func findRangeAnnotations(source []byte, forceComment bool) ([]extent, []extent, []extent, error) {
+	fset, node, err := parseSource(source)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	a := rangeAnnotationsFromAST(fset, node, annotationOptions{
+		forceComment: forceComment,
+		now:          time.Now(),
+	})
+
+	return a.valid, a.withoutComment, a.unmatched, nil
+}
+
+// rangeAnnotationsFromAST returns extents of lines between coverage-ignore-start
+// and coverage-ignore-end annotations as valid annotations. Start annotations are
+// checked for explanation and metadata in the same way as coverage-ignore annotations,
+// and range with expired or invalid start annotation does not exclude code. Annotations which
+// do not have matching pair are returned as unmatched. Ranges can not be nested.
+//
+//nolint:cyclop // relax
+func rangeAnnotationsFromAST(
 	fset *token.FileSet,
 	node *ast.File,
-	forceComment bool,
-) ([]extent, []extent) {
-	var validAnnotations, annotationsWithoutComment []extent
+	opts annotationOptions,
+) annotations {
+	var (
+		res         annotations
+		start       *extent
+		startIgnore bool // start annotation is expired or invalid
+	)
 
-	for _, c := range node.Comments {
-		if !strings.Contains(c.Text(), IgnoreText) {
-			continue // does not have annotation continue to next comment
+	for _, cg := range node.Comments {
+		for _, c := range cg.List {
+			e := newExtent(fset, c)
+
+			switch rangeAnnotation(c) {
+			case IgnoreStartText:
+				if start != nil {
+					res.unmatched = append(res.unmatched, e) // nested range
+					continue
+				}
+
+				start = &e
+				meta := parseAnnotationMeta(c.Text, IgnoreStartText)
+
+				startIgnore = meta.invalid() || meta.expired(opts.now)
+				if startIgnore {
+					if meta.invalid() {
+						res.invalid = append(res.invalid, e)
+					} else {
+						res.expired = append(res.expired, e)
+					}
+
+					continue // expired or invalid range does not exclude code
+				}
+
+				if opts.forceComment && !hasExplanation(meta.comment, IgnoreStartText) {
+					res.withoutComment = append(res.withoutComment, e)
+				}
+
+				if opts.forceIssue && meta.issue == "" {
+					res.withoutIssue = append(res.withoutIssue, e)
+				}
+
+			case IgnoreEndText:
+				if start == nil {
+					res.unmatched = append(res.unmatched, e)
+					continue
+				}
+
+				if !startIgnore {
+					res.valid = append(res.valid, extent{
+						StartLine: start.StartLine,
+						StartCol:  start.StartCol,
+						EndLine:   e.EndLine,
+						EndCol:    e.EndCol,
+					})
+				}
+
+				start = nil
+			}
 		}
+	}
 
-		validAnnotations = append(validAnnotations, newExtent(fset, c))
+	if start != nil {
+		res.unmatched = append(res.unmatched, *start)
+	}
 
-		if forceComment && !hasComment(c.Text()) {
-			annotationsWithoutComment = append(annotationsWithoutComment, newExtent(fset, c))
+	return res
+}
+
+// rangeAnnotation returns range annotation which comment starts with, or
+// empty string when comment is not range annotation.
+func rangeAnnotation(c *ast.Comment) string {
+	text := commentText(c)
+
+	for _, a := range []string{IgnoreStartText, IgnoreEndText} {
+		if strings.HasPrefix(text, a) {
+			return a
 		}
 	}
 
-	return validAnnotations, annotationsWithoutComment
+	return ""
+}
+
+func isRangeAnnotation(c *ast.Comment) bool {
+	return rangeAnnotation(c) != ""
+}
+
+// isDirective returns true when comment starts with directive, which is
+// either coverage-ignore-file or coverage-ignore-package.
+func isDirective(c *ast.Comment) bool {
+	text := commentText(c)
+
+	return strings.HasPrefix(text, IgnoreFileText) || strings.HasPrefix(text, IgnorePackageText)
+}
+
+// hasExplanation checks if annotation in comment text is followed by an explanation
+func hasExplanation(text, annotation string) bool {
+	_, after, _ := strings.Cut(text, annotation)
+	return strings.Trim(after, " \t*/") != ""
 }
 
 // hasComment checks if the coverage-ignore annotation has an explanation comment
@@ -323,6 +731,15 @@ func findFuncsAndBlocks(source []byte) ([]extent, []extent, error) {
 	return f, b, nil
 }
 
+func findFuncNames(source []byte) (map[int]string, error) {
+	fset, node, err := parseSource(source)
+	if err != nil {
+		return nil, err
+	}
+
+	return funcNamesFromAST(fset, node), nil
+}
+
 func funcsAndBlocksFromAST(fset *token.FileSet, node *ast.File) ([]extent, []extent) {
 	v := &visitor{fset: fset}
 	ast.Walk(v, node)
@@ -330,6 +747,51 @@ func funcsAndBlocksFromAST(fset *token.FileSet, node *ast.File) ([]extent, []ext
 	return v.funcs, v.blocks
 }
 
+// funcNamesFromAST returns names of declared functions, keyed by start line of
+// function body. Methods are named with receiver type, e.g. `(*T).Method`.
+func funcNamesFromAST(fset *token.FileSet, node *ast.File) map[int]string {
+	names := make(map[int]string)
+
+	for _, d := range node.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		name := fn.Name.Name
+		if fn.Recv != nil && len(fn.Recv.List) > 0 {
+			name = receiverName(fn.Recv.List[0].Type) + "." + name
+		}
+
+		names[fset.Position(fn.Body.Pos()).Line] = name
+	}
+
+	return names
+}
+
+func receiverName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return "(*" + receiverName(e.X) + ")"
+	case *ast.IndexExpr: // generic type with single type parameter
+		return receiverName(e.X)
+	case *ast.IndexListExpr: // generic type with multiple type parameters
+		return receiverName(e.X)
+	case *ast.Ident:
+		return e.Name
+	}
+
+	return "" // coverage-ignore
+}
+
+func nameFunctions(funcs []FuncStats, names map[int]string) []FuncStats {
+	for i := range funcs {
+		funcs[i].Name = names[funcs[i].StartLine]
+	}
+
+	return funcs
+}
+
 type visitor struct {
 	fset   *token.FileSet
 	funcs  []extent
@@ -344,16 +806,26 @@ func (v *visitor) Visit(node ast.Node) ast.Visitor {
 
 	case *ast.IfStmt:
 		v.addBlock(n.Body)
+
+		if e, ok := n.Else.(*ast.BlockStmt); ok { // else-if is visited as IfStmt
+			v.addBlock(e)
+		}
 	case *ast.SwitchStmt:
 		v.addBlock(n.Body)
 	case *ast.TypeSwitchStmt:
 		v.addBlock(n.Body)
-	case *ast.SelectStmt: // coverage-ignore
+	case *ast.SelectStmt:
 		v.addBlock(n.Body)
+	case *ast.CaseClause:
+		v.addBlock(n)
+	case *ast.CommClause:
+		v.addBlock(n)
 	case *ast.ForStmt:
 		v.addBlock(n.Body)
 	case *ast.RangeStmt:
 		v.addBlock(n.Body)
+	case *ast.FuncLit:
+		v.addBlock(n.Body)
 	}
 
 	return v
@@ -382,19 +854,37 @@ func newExtent(fset *token.FileSet, n ast.Node) extent {
 	}
 }
 
-func findExtentWithStartLine(ee []extent, line int) (extent, bool) {
+// findInnermostExtent returns the innermost extent which contains line.
+// Extents are either nested or disjoint, so the innermost extent is the one
+// which starts last.
+func findInnermostExtent(ee []extent, line int) (extent, bool) {
+	var (
+		res   extent
+		found bool
+	)
+
 	for _, e := range ee {
-		if e.StartLine <= line && e.EndLine >= line {
-			return e, true
+		if e.StartLine > line || e.EndLine < line {
+			continue
+		}
+
+		if !found || e.StartLine > res.StartLine ||
+			(e.StartLine == res.StartLine && e.StartCol > res.StartCol) {
+			res, found = e, true
 		}
 	}
 
-	return extent{}, false
+	return res, found
+}
+
+// containsPos returns true when position is inside of extent, where extent
+// start is inclusive and end is exclusive.
+func (e extent) containsPos(line, col int) bool {
+	return !posBefore(line, col, e.StartLine, e.StartCol) && posBefore(line, col, e.EndLine, e.EndCol)
 }
 
-func hasExtentWithStartLine(ee []extent, startLine int) bool {
-	_, found := findExtentWithStartLine(ee, startLine)
-	return found
+func posBefore(line1, col1, line2, col2 int) bool {
+	return line1 < line2 || (line1 == line2 && col1 < col2)
 }
 
 func pluckStartLine(extents []extent) []int {
@@ -406,40 +896,86 @@ func pluckStartLine(extents []extent) []int {
 	return res
 }
 
-func sumCoverage(profile *cover.Profile, funcs, blocks, annotations []extent) Stats {
+func sumCoverage(profile *cover.Profile, funcs, blocks, annotations, ranges []extent) Stats {
 	s := Stats{}
+	withHitCount := hasHitCount(profile.Mode)
+	ignored := make(map[int]int64)
 
 	for _, f := range funcs {
-		c, t, ul := coverage(profile, f, blocks, annotations)
-		s.Total += t
-		s.Covered += c
-		s.UncoveredLines = append(s.UncoveredLines, ul...)
+		fc := coverage(profile, f, blocks, annotations, ranges)
+		s.Total += fc.total
+		s.Covered += fc.covered
+		s.CoveredLines = append(s.CoveredLines, fc.coveredLines...)
+		s.UncoveredLines = append(s.UncoveredLines, fc.uncoveredLines...)
+		s.IgnoredLines = append(s.IgnoredLines, fc.ignoredLines...)
+
+		for line, count := range fc.ignored {
+			ignored[line] += count
+			s.Ignored += count
+		}
+
+		if withHitCount {
+			s.Blocks = append(s.Blocks, fc.blocks...)
+		}
+
+		if fc.total > 0 {
+			s.Functions = append(s.Functions, FuncStats{
+				StartLine: f.StartLine,
+				EndLine:   f.EndLine,
+				Total:     fc.total,
+				Covered:   fc.covered,
+			})
+		}
 	}
 
+	s.CoveredLines = dedup(s.CoveredLines)
 	s.UncoveredLines = dedup(s.UncoveredLines)
+	s.IgnoredLines = dedup(s.IgnoredLines)
+
+	for _, line := range slices.Sorted(maps.Keys(ignored)) {
+		s.IgnoredByAnnotation = append(s.IgnoredByAnnotation, AnnotationStats{
+			Line:    line,
+			Ignored: ignored[line],
+		})
+	}
 
 	return s
 }
 
+// hasHitCount returns true if profile mode records number of times
+// each block was executed.
+func hasHitCount(mode string) bool {
+	return mode == "count" || mode == "atomic"
+}
+
+type funcCoverage struct {
+	covered, total int64
+	coveredLines   []int
+	uncoveredLines []int
+	ignoredLines   []int
+	blocks         []Block
+	ignored        map[int]int64 // number of ignored statements keyed by annotation line
+}
+
 // coverage returns the number of covered and total statements in the function,
-// along with the list of uncovered line numbers.
+// along with the list of covered and uncovered line numbers and executed blocks.
 //
 //nolint:cyclop,gocognit,maintidx // relax
 func coverage(
 	profile *cover.Profile,
 	f extent,
-	blocks, annotations []extent,
-) (int64, int64, []int) {
-	if hasExtentWithStartLine(annotations, f.StartLine) {
-		// case when entire function is ignored
-		return 0, 0, nil
+	blocks, annotations, ranges []extent,
+) funcCoverage {
+	var fc funcCoverage
+
+	fc.ignored = make(map[int]int64)
+	ignore := func(b cover.ProfileBlock, annotation int) {
+		fc.ignoredLines = appendLines(fc.ignoredLines, b)
+		fc.ignored[annotation] += int64(b.NumStmt)
 	}
 
-	var (
-		covered, total int64
-		skip           extent
-		uncoveredLines []int
-	)
+	// case when entire function is ignored
+	funcAnnotation, ignoreFunc := findInnermostExtent(annotations, f.StartLine)
 
 	// the blocks are sorted, so we can stop counting as soon as
 	// we reach the end of the relevant block.
@@ -454,32 +990,102 @@ func coverage(
 			continue
 		}
 
-		if b.StartLine < skip.EndLine || (b.StartLine == skip.EndLine && b.StartCol <= skip.EndCol) {
-			// this block has comment annotation
+		if ignoreFunc {
+			ignore(b, funcAnnotation.StartLine)
 			continue
 		}
 
-		// add block to coverage statistics only if it was not ignored using comment annotations
-		if hasExtentWithStartLine(annotations, b.StartLine) {
-			if e, found := findExtentWithStartLine(blocks, b.StartLine); found {
-				skip = e
-			}
+		if r, ok := findRange(ranges, b); ok {
+			ignore(b, r.StartLine)
+			continue
+		}
+
+		if a, ok := findAnnotatedBlock(blocks, annotations, b); ok {
+			// this block is inside of block with comment annotation
+			ignore(b, a.StartLine)
+			continue
+		}
 
+		// add block to coverage statistics only if it was not ignored using comment
+		// annotation on line where block starts. when annotated block opens later on
+		// the same line (eg. `if` condition before `{`), annotation belongs to that block.
+		if a, ok := findInnermostExtent(annotations, b.StartLine); ok && !hasBlockAfter(blocks, b) {
+			ignore(b, a.StartLine)
 			continue
 		}
 
-		total += int64(b.NumStmt)
+		fc.total += int64(b.NumStmt)
+		fc.blocks = append(fc.blocks, Block{
+			StartLine: b.StartLine,
+			EndLine:   b.EndLine,
+			NumStmt:   b.NumStmt,
+			Count:     b.Count,
+		})
 
 		if b.Count > 0 {
-			covered += int64(b.NumStmt)
+			fc.covered += int64(b.NumStmt)
+			fc.coveredLines = appendLines(fc.coveredLines, b)
 		} else {
-			for i := range (b.EndLine - b.StartLine) + 1 {
-				uncoveredLines = append(uncoveredLines, b.StartLine+i)
-			}
+			fc.uncoveredLines = appendLines(fc.uncoveredLines, b)
+		}
+	}
+
+	return fc
+}
+
+// findAnnotatedBlock returns annotation of the outermost block which contains start
+// of profile block, and which has annotation on the line where it opens. Position
+// of block start depends on go version; it can be at `{` (or `:` of case clause)
+// or at the first statement of block, and both are contained in the block.
+func findAnnotatedBlock(blocks, annotations []extent, b cover.ProfileBlock) (extent, bool) {
+	var (
+		res, outer extent
+		found      bool
+	)
+
+	for _, e := range blocks {
+		if !e.containsPos(b.StartLine, b.StartCol) {
+			continue
+		}
+
+		a, ok := findInnermostExtent(annotations, e.StartLine)
+		if !ok {
+			continue
+		}
+
+		if !found || posBefore(e.StartLine, e.StartCol, outer.StartLine, outer.StartCol) {
+			res, outer, found = a, e, true
+		}
+	}
+
+	return res, found
+}
+
+// hasBlockAfter returns true when some block opens on the line where profile
+// block starts, after its start.
+func hasBlockAfter(blocks []extent, b cover.ProfileBlock) bool {
+	return slices.ContainsFunc(blocks, func(e extent) bool {
+		return e.StartLine == b.StartLine && e.StartCol > b.StartCol
+	})
+}
+
+// findRange returns range which block falls inside of
+func findRange(ranges []extent, b cover.ProfileBlock) (extent, bool) {
+	for _, r := range ranges {
+		if b.StartLine >= r.StartLine && b.EndLine <= r.EndLine {
+			return r, true
 		}
 	}
 
-	return covered, total, uncoveredLines
+	return extent{}, false
+}
+
+func appendLines(lines []int, b cover.ProfileBlock) []int {
+	for i := range (b.EndLine - b.StartLine) + 1 {
+		lines = append(lines, b.StartLine+i)
+	}
+
+	return lines
 }
 
 func dedup(ss []int) []int {
@@ -1,8 +1,11 @@
 package coverage_test
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/tools/cover"
@@ -21,6 +24,10 @@ const (
 	profileNOK              = testdataDir + testdata.ProfileNOK
 	profileNOKInvalidLength = testdataDir + testdata.ProfileNOKInvalidLength
 	profileNOKInvalidData   = testdataDir + testdata.ProfileNOKInvalidData
+	covDataDir              = testdataDir + testdata.CovDataDir
+	covDataProfile          = testdataDir + testdata.CovDataProfile
+	annotationsDir          = testdataDir + testdata.AnnotationsDir
+	annotationsProfile      = testdataDir + testdata.AnnotationsProfile
 
 	prefix        = "github.com/vladopajic/go-test-coverage/v2"
 	coverFilename = "pkg/testcoverage/coverage/cover.go"
@@ -86,6 +93,110 @@ func Test_GenerateCoverageStats(t *testing.T) {
 	assert.NotContains(t, `badge/generate.go`, stats4[0].Name)
 }
 
+func Test_GenerateCoverageDirectives(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		return
+	}
+
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+
+		file := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+		assert.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+	}
+
+	writeFile("go.mod", "module example.com/m\n")
+	writeFile("foo/foo.go", "package foo\n\nfunc Foo() int {\n\treturn 1\n}\n")
+	writeFile("foo/gen.go", "// Code generated by tool. DO NOT EDIT.\n"+
+		"//coverage-ignore-file - generated code\n\npackage foo\n\nfunc Gen() int {\n\treturn 1\n}\n")
+	writeFile("foo/foo.pb.go", "// Code generated by protoc-gen-go. DO NOT EDIT.\n\n"+
+		"package foo\n\nfunc Pb() int {\n\treturn 1\n}\n")
+	writeFile("foo/late.go", "package foo\n\n// coverage-ignore-file not in header\nfunc Late() int {\n\treturn 1\n}\n")
+	writeFile("bar/doc.go", "/* coverage-ignore-package: experimental package */\n\n// Package bar.\npackage bar\n")
+	writeFile("bar/bar.go", "package bar\n\nfunc Bar() int {\n\treturn 1\n}\n")
+	writeFile("baz/doc.go", "invalid source")
+	writeFile("baz/baz.go", "package baz\n\nfunc Baz() int {\n\treturn 1\n}\n")
+	writeFile("cover.out", "mode: set\n"+
+		"example.com/m/foo/foo.go:3.16,5.2 1 1\n"+
+		"example.com/m/foo/gen.go:6.16,8.2 1 0\n"+
+		"example.com/m/foo/foo.pb.go:5.15,7.2 1 0\n"+
+		"example.com/m/foo/late.go:4.17,6.2 1 1\n"+
+		"example.com/m/bar/bar.go:3.16,5.2 1 0\n"+
+		"example.com/m/baz/baz.go:3.16,5.2 1 0\n")
+
+	stats, exclusions, err := GenerateCoverage(Config{
+		Profiles:  []string{filepath.Join(dir, "cover.out")},
+		SourceDir: dir,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, stats, 4)
+	assert.Equal(t, []Exclusion{
+		{Name: "bar/bar.go", By: IgnorePackageText, Reason: "experimental package"},
+		{Name: "foo/gen.go", By: IgnoreFileText, Reason: "generated code"},
+	}, exclusions)
+
+	// misplaced directive is reported and it does not ignore code
+	late := StatsSearchMap(stats)["foo/late.go"]
+	assert.Equal(t, []int{3}, late.UnmatchedAnnotations)
+	assert.Equal(t, int64(0), late.Ignored)
+	assert.Equal(t, int64(1), late.Total)
+
+	// generated files are excluded before directives are checked
+	stats, exclusions, err = GenerateCoverage(Config{
+		Profiles:         []string{filepath.Join(dir, "cover.out")},
+		SourceDir:        dir,
+		ExcludeGenerated: true,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, stats, 3)
+	assert.Equal(t, []Exclusion{
+		{Name: "bar/bar.go", By: IgnorePackageText, Reason: "experimental package"},
+		{Name: "foo/foo.pb.go", By: ExcludedGenerated, Reason: "Code generated by protoc-gen-go. DO NOT EDIT."},
+		{Name: "foo/gen.go", By: ExcludedGenerated, Reason: "Code generated by tool. DO NOT EDIT."},
+	}, exclusions)
+}
+
+func Test_GenerateCoverageFullyIgnored(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		return
+	}
+
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	writeFile("go.mod", "module example.com/m\n")
+	writeFile("foo.go", "package m\n\nfunc Foo() int {\n\treturn 1\n}\n")
+	writeFile("ignored.go", "package m\n\nfunc Ignored() int { // coverage-ignore\n\treturn 1\n}\n")
+	writeFile("empty.go", "package m\n\nfunc Empty() {}\n")
+	writeFile("cover.out", "mode: set\n"+
+		"example.com/m/foo.go:3.16,5.2 1 1\n"+
+		"example.com/m/ignored.go:3.20,5.2 1 0\n"+
+		"example.com/m/empty.go:3.14,3.15 0 0\n")
+
+	// file where every statement is ignored is kept so ignored statements are accounted,
+	// while file without statements is dropped
+	stats, _, err := GenerateCoverage(Config{
+		Profiles:  []string{filepath.Join(dir, "cover.out")},
+		SourceDir: dir,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, stats, 2)
+
+	ignored := StatsSearchMap(stats)["ignored.go"]
+	assert.Equal(t, int64(0), ignored.Total)
+	assert.Equal(t, int64(1), ignored.Ignored)
+}
+
 func Test_findFile(t *testing.T) {
 	t.Parallel()
 
@@ -178,6 +289,205 @@ func Test_findAnnotationsWithComment(t *testing.T) {
 	assert.Empty(t, withoutComment)
 }
 
+func Test_findAnnotationsWithMeta(t *testing.T) {
+	t.Parallel()
+
+	const source = `
+	package foo
+	func foo() int {
+		a := 0
+		if a > 1 { // coverage-ignore until=2027-01-01 issue=PROJ-123: tracked
+			a = 1
+		}
+		if a > 2 { // coverage-ignore until=2026-06-30
+			a = 2
+		}
+		if a > 3 { // coverage-ignore issue=#42 - tracked
+			a = 3
+		}
+		if a > 4 { // coverage-ignore until=someday
+			a = 4
+		}
+		if a > 5 { // coverage-ignore reason mentions issue=PROJ-1
+			a = 5
+		}
+		return a
+	}
+	`
+
+	now := time.Date(2026, 6, 30, 23, 0, 0, 0, time.UTC)
+
+	valid, withoutComment, withoutIssue, expired, invalid := FindAnnotationsWithMeta(
+		[]byte(source), true, true, now,
+	)
+	assert.Equal(t, []int{5, 8, 11, 17}, PluckStartLine(valid))
+	assert.Equal(t, []int{8}, PluckStartLine(withoutComment))
+	assert.Equal(t, []int{8, 17}, PluckStartLine(withoutIssue))
+	assert.Empty(t, expired)
+	assert.Equal(t, []int{14}, PluckStartLine(invalid))
+
+	// annotations expire after the day of until date
+	valid, withoutComment, withoutIssue, expired, invalid = FindAnnotationsWithMeta(
+		[]byte(source), false, false, now.Add(time.Hour),
+	)
+	assert.Equal(t, []int{5, 11, 17}, PluckStartLine(valid))
+	assert.Empty(t, withoutComment)
+	assert.Empty(t, withoutIssue)
+	assert.Equal(t, []int{8}, PluckStartLine(expired))
+	assert.Equal(t, []int{14}, PluckStartLine(invalid))
+}
+
+func Test_findRangeAnnotations(t *testing.T) {
+	t.Parallel()
+
+	_, _, _, err := FindRangeAnnotations(nil, false)
+	assert.Error(t, err)
+
+	const source = `
+	package foo
+	func foo() int {
+		a := 0
+		// coverage-ignore-start - defensive code
+		if a > 5 {
+			a = 5
+		}
+		// coverage-ignore-end
+		/* coverage-ignore-start */
+		if a < 0 {
+			a = 0
+		}
+		/* coverage-ignore-end */
+		return a
+	}
+	`
+
+	ranges, withoutComment, unmatched, err := FindRangeAnnotations([]byte(source), true)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{5, 10}, PluckStartLine(ranges))
+	assert.Equal(t, []int{9, 14}, pluckEndLine(ranges))
+	assert.Equal(t, []int{10}, PluckStartLine(withoutComment))
+	assert.Empty(t, unmatched)
+
+	_, withoutComment, _, err = FindRangeAnnotations([]byte(source), false)
+	assert.NoError(t, err)
+	assert.Empty(t, withoutComment)
+
+	// range annotations are not handled as regular annotations
+	annotations, _, err := FindAnnotations([]byte(source), true)
+	assert.NoError(t, err)
+	assert.Empty(t, annotations)
+}
+
+func Test_findRangeAnnotationsWithMeta(t *testing.T) {
+	t.Parallel()
+
+	const source = `
+	package foo
+	func foo() int {
+		a := 0
+		// coverage-ignore-start until=2027-01-01 issue=PROJ-123: tracked
+		a = 1
+		// coverage-ignore-end
+		// coverage-ignore-start until=2026-06-30
+		a = 2
+		// coverage-ignore-end
+		/* coverage-ignore-start issue=#42 - tracked */
+		a = 3
+		/* coverage-ignore-end */
+		// coverage-ignore-start until=someday: reason
+		a = 4
+		// coverage-ignore-end
+		return a
+	}
+	`
+
+	now := time.Date(2026, 6, 30, 23, 0, 0, 0, time.UTC)
+
+	valid, withoutComment, withoutIssue, expired, invalid := FindRangeAnnotationsWithMeta(
+		[]byte(source), true, true, now,
+	)
+	assert.Equal(t, []int{5, 8, 11}, PluckStartLine(valid))
+	assert.Equal(t, []int{8}, PluckStartLine(withoutComment))
+	assert.Equal(t, []int{8}, PluckStartLine(withoutIssue))
+	assert.Empty(t, expired)
+	assert.Equal(t, []int{14}, PluckStartLine(invalid))
+
+	// ranges expire after the day of until date, and expired ranges do not exclude code
+	valid, withoutComment, withoutIssue, expired, invalid = FindRangeAnnotationsWithMeta(
+		[]byte(source), false, false, now.Add(time.Hour),
+	)
+	assert.Equal(t, []int{5, 11}, PluckStartLine(valid))
+	assert.Empty(t, withoutComment)
+	assert.Empty(t, withoutIssue)
+	assert.Equal(t, []int{8}, PluckStartLine(expired))
+	assert.Equal(t, []int{14}, PluckStartLine(invalid))
+
+	// expired and invalid start annotations still have matching pair
+	_, _, unmatched, err := FindRangeAnnotations([]byte(source), false)
+	assert.NoError(t, err)
+	assert.Empty(t, unmatched)
+}
+
+func Test_findRangeAnnotationsUnmatched(t *testing.T) {
+	t.Parallel()
+
+	const source = `
+	package foo
+	func foo() int {
+		a := 0
+		// coverage-ignore-end
+		// coverage-ignore-start
+		if a > 5 {
+			// coverage-ignore-start
+			a = 5
+		}
+		// coverage-ignore-end
+		// this comment mentions coverage-ignore-start, but it is not annotation
+		// coverage-ignore-start
+		return a
+	}
+	`
+
+	ranges, _, unmatched, err := FindRangeAnnotations([]byte(source), false)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{6}, PluckStartLine(ranges))
+	assert.Equal(t, []int{11}, pluckEndLine(ranges))
+	assert.Equal(t, []int{5, 8, 13}, PluckStartLine(unmatched))
+}
+
+func Test_findAnnotationsNextToRangeAnnotations(t *testing.T) {
+	t.Parallel()
+
+	const source = `
+	package foo
+	func foo() int {
+		a := 0
+		// coverage-ignore-start - defensive code
+		a = 1
+		// coverage-ignore-end
+		// coverage-ignore - annotation in same comment group as range end
+		if a > 5 { // coverage-ignore - annotation in same comment group as range start
+			// coverage-ignore-start - defensive code
+			a = 5
+		}
+		// coverage-ignore-end
+		return a
+	}
+	`
+
+	annotations, withoutComment, err := FindAnnotations([]byte(source), true)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{8, 9}, PluckStartLine(annotations))
+	assert.Equal(t, []int{8, 9}, pluckEndLine(annotations))
+	assert.Empty(t, withoutComment)
+
+	ranges, _, unmatched, err := FindRangeAnnotations([]byte(source), true)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{5, 10}, PluckStartLine(ranges))
+	assert.Equal(t, []int{7, 13}, pluckEndLine(ranges))
+	assert.Empty(t, unmatched)
+}
+
 func Test_findFuncs(t *testing.T) {
 	t.Parallel()
 
@@ -227,6 +537,39 @@ func Test_findFuncs(t *testing.T) {
 	}, blocks)
 }
 
+func Test_findFuncNames(t *testing.T) {
+	t.Parallel()
+
+	_, err := FindFuncNames(nil)
+	assert.Error(t, err)
+
+	const source = `
+	package foo
+	type T struct{}
+	type G[K any, V any] struct{}
+	type H[K any] struct{}
+	func foo() int {
+		return 1
+	}
+	func (T) bar() {}
+	func (t *T) baz() {
+	}
+	func (g G[K, V]) qux() {}
+	func (h *H[K]) quux() {}
+	func external()
+	`
+
+	names, err := FindFuncNames([]byte(source))
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]string{
+		6:  "foo",
+		9:  "T.bar",
+		10: "(*T).baz",
+		12: "G.qux",
+		13: "(*H).quux",
+	}, names)
+}
+
 func Test_findFilePathMatchingSearch(t *testing.T) {
 	t.Parallel()
 
@@ -264,22 +607,229 @@ func Test_sumCoverage(t *testing.T) {
 		{StartLine: 12, EndLine: 20, NumStmt: 5},
 	}}
 
-	s := SumCoverage(profile, funcs, nil, nil)
+	s := SumCoverage(profile, funcs, nil, nil, nil)
 	expected := Stats{Total: 10, Covered: 0, UncoveredLines: []int{
 		1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 12, 13, 14, 15, 16, 17, 18, 19, 20,
+	}, Functions: []FuncStats{
+		{StartLine: 1, EndLine: 10, Total: 5},
+		{StartLine: 12, EndLine: 20, Total: 5},
 	}}
 	assert.Equal(t, expected, s)
 
 	// Coverage should be empty when every function is excluded
-	s = SumCoverage(profile, funcs, nil, funcs)
-	assert.Equal(t, Stats{Total: 0, Covered: 0}, s)
+	s = SumCoverage(profile, funcs, nil, funcs, nil)
+	assert.Equal(t, Stats{Total: 0, Covered: 0, Ignored: 10, IgnoredLines: []int{
+		1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 12, 13, 14, 15, 16, 17, 18, 19, 20,
+	}, IgnoredByAnnotation: []AnnotationStats{
+		{Line: 1, Ignored: 5},
+		{Line: 12, Ignored: 5},
+	}}, s)
 
 	// Case when annotations is set on block (it should ignore whole block)
 	annotations := []Extent{{StartLine: 4, EndLine: 4}}
 	blocks := []Extent{{StartLine: 4, EndLine: 10}}
-	s = SumCoverage(profile, funcs, blocks, annotations)
-	expected = Stats{Total: 7, Covered: 0, UncoveredLines: []int{
+	s = SumCoverage(profile, funcs, blocks, annotations, nil)
+	expected = Stats{Total: 7, Covered: 0, Ignored: 3, UncoveredLines: []int{
 		1, 2, 3, 12, 13, 14, 15, 16, 17, 18, 19, 20,
+	}, IgnoredLines: []int{4, 5, 6, 7, 8, 9, 10}, IgnoredByAnnotation: []AnnotationStats{
+		{Line: 4, Ignored: 3},
+	}, Functions: []FuncStats{
+		{StartLine: 1, EndLine: 10, Total: 2},
+		{StartLine: 12, EndLine: 20, Total: 5},
 	}}
 	assert.Equal(t, expected, s)
+
+	// Case when range annotation is set (it should ignore only blocks inside range)
+	ranges := []Extent{{StartLine: 4, EndLine: 6}}
+	s = SumCoverage(profile, funcs, nil, nil, ranges)
+	expected = Stats{Total: 8, Covered: 0, Ignored: 2, UncoveredLines: []int{
+		1, 2, 3, 6, 7, 8, 9, 10, 12, 13, 14, 15, 16, 17, 18, 19, 20,
+	}, IgnoredLines: []int{4, 5, 6}, IgnoredByAnnotation: []AnnotationStats{
+		{Line: 4, Ignored: 2},
+	}, Functions: []FuncStats{
+		{StartLine: 1, EndLine: 10, Total: 3},
+		{StartLine: 12, EndLine: 20, Total: 5},
+	}}
+	assert.Equal(t, expected, s)
+
+	// Covered blocks should be reported as covered lines
+	profile.Blocks[0].Count = 1
+	profile.Blocks[5].Count = 2
+	s = SumCoverage(profile, funcs, nil, nil, nil)
+	expected = Stats{
+		Total:          10,
+		Covered:        6,
+		CoveredLines:   []int{1, 2, 12, 13, 14, 15, 16, 17, 18, 19, 20},
+		UncoveredLines: []int{2, 3, 4, 5, 6, 7, 8, 9, 10},
+		Functions: []FuncStats{
+			{StartLine: 1, EndLine: 10, Total: 5, Covered: 1},
+			{StartLine: 12, EndLine: 20, Total: 5, Covered: 5},
+		},
+	}
+	assert.Equal(t, expected, s)
+
+	// Hit counts should be preserved for profiles with `count` mode
+	profile.Mode = "count"
+	s = SumCoverage(profile, funcs, nil, nil, nil)
+	assert.Len(t, s.Blocks, 6)
+	assert.Equal(t, Block{StartLine: 1, EndLine: 2, NumStmt: 1, Count: 1}, s.Blocks[0])
+	assert.Equal(t, Block{StartLine: 12, EndLine: 20, NumStmt: 5, Count: 2}, s.Blocks[5])
+}
+
+func Test_sumCoverageAnnotationTargets(t *testing.T) {
+	t.Parallel()
+
+	const source = `
+	package foo
+	func Else(a int) int {
+		if a > 0 {
+			a++
+		} else { // coverage-ignore
+			if a < -10 {
+				a = 0
+			}
+			a--
+		}
+		return a
+	}
+	func Case(a int) int {
+		switch a {
+		case 1: // coverage-ignore
+			a++
+		case 2:
+			a--
+		}
+		return a
+	}
+	func Select(c chan int) int {
+		a := 0
+		select {
+		case v := <-c: // coverage-ignore
+			a = v
+		default:
+			a = 1
+		}
+		return a
+	}
+	func FuncLit() int {
+		a := 0
+		f := func() { // coverage-ignore
+			if a > 0 {
+				a++
+			}
+		}
+		f()
+		for range 3 {
+			if a > 10 { // coverage-ignore
+				a = 0
+			}
+			a += 2
+		}
+		return a
+	}
+	`
+
+	funcs, blocks, err := FindFuncsAndBlocks([]byte(source))
+	assert.NoError(t, err)
+
+	annotations, _, err := FindAnnotations([]byte(source), false)
+	assert.NoError(t, err)
+
+	block := func(startLine, startCol, endLine, endCol, numStmt int) cover.ProfileBlock {
+		return cover.ProfileBlock{
+			StartLine: startLine, StartCol: startCol,
+			EndLine: endLine, EndCol: endCol,
+			NumStmt: numStmt,
+		}
+	}
+	// blocks start at `{` (or `:` of case clause), as in profiles made with older
+	// go versions. profile of newer versions is tested with real generated profile.
+	profile := &cover.Profile{Blocks: []cover.ProfileBlock{
+		// Else
+		block(3, 23, 4, 12, 1),
+		block(4, 12, 6, 4, 1),
+		block(6, 10, 7, 15, 1),
+		block(7, 15, 9, 5, 1),
+		block(10, 4, 10, 7, 1),
+		block(12, 3, 12, 11, 1),
+		// Case
+		block(14, 23, 15, 12, 1),
+		block(16, 10, 17, 7, 1),
+		block(18, 10, 19, 7, 1),
+		block(21, 3, 21, 11, 1),
+		// Select
+		block(23, 30, 25, 10, 2),
+		block(26, 17, 27, 9, 1),
+		block(28, 11, 29, 9, 1),
+		block(31, 3, 31, 11, 1),
+		// FuncLit
+		block(33, 21, 41, 15, 4),
+		block(35, 15, 36, 13, 1),
+		block(36, 13, 38, 5, 1),
+		block(41, 15, 42, 14, 1),
+		block(42, 14, 44, 5, 1),
+		block(45, 4, 45, 10, 1),
+		block(47, 3, 47, 11, 1),
+	}}
+
+	// only blocks of annotated else branch, case clauses and func literal
+	// should be ignored, without neighbouring blocks
+	s := SumCoverage(profile, funcs, blocks, annotations, nil)
+	assert.Equal(t, []int{6, 7, 8, 9, 10, 16, 17, 26, 27, 35, 36, 37, 38, 42, 43, 44}, s.IgnoredLines)
+	assert.Equal(t, []FuncStats{
+		{StartLine: 3, EndLine: 13, Total: 3},
+		{StartLine: 14, EndLine: 22, Total: 3},
+		{StartLine: 23, EndLine: 32, Total: 4},
+		{StartLine: 33, EndLine: 48, Total: 7},
+	}, s.Functions)
+	assert.Equal(t, int64(8), s.Ignored)
+	assert.Equal(t, []AnnotationStats{
+		{Line: 6, Ignored: 3},
+		{Line: 16, Ignored: 1},
+		{Line: 26, Ignored: 1},
+		{Line: 35, Ignored: 2},
+		{Line: 42, Ignored: 1},
+	}, s.IgnoredByAnnotation)
+}
+
+func Test_GenerateCoverageAnnotationTargets(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		return
+	}
+
+	// profile is made with `go test -coverprofile`, so positions of blocks are
+	// the same as in real runs
+	stats, _, err := GenerateCoverage(Config{
+		Profiles:  []string{annotationsProfile},
+		SourceDir: annotationsDir,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, stats, 1)
+
+	// only blocks of annotated targets are ignored, and everything else is covered
+	s := stats[0]
+	assert.Equal(t, "targets.go", s.Name)
+	assert.Equal(t, []int{5, 6, 15, 16, 17, 19, 30, 43, 51, 52, 67}, s.IgnoredLines)
+	assert.Equal(t, []AnnotationStats{
+		{Line: 4, Ignored: 1},  // if
+		{Line: 14, Ignored: 3}, // else
+		{Line: 29, Ignored: 1}, // case
+		{Line: 42, Ignored: 1}, // select case
+		{Line: 50, Ignored: 1}, // func literal
+		{Line: 67, Ignored: 1}, // statement
+	}, s.IgnoredByAnnotation)
+	assert.Equal(t, int64(8), s.Ignored)
+	assert.Equal(t, s.Total, s.Covered)
+	assert.Empty(t, s.UncoveredLines)
+}
+
+func pluckEndLine(extents []Extent) []int {
+	res := make([]int, len(extents))
+	for i, e := range extents {
+		res[i] = e.EndLine
+	}
+
+	return res
 }
@@ -1,14 +1,18 @@
 package coverage
 
+import "time"
+
 var (
 	FindFileCreator            = findFileCreator
 	FindAnnotations            = findAnnotations
+	FindRangeAnnotations       = findRangeAnnotations
 	FindFuncsAndBlocks         = findFuncsAndBlocks
 	ParseProfiles              = parseProfiles
 	SumCoverage                = sumCoverage
 	FindGoModFile              = findGoModFile
 	PluckStartLine             = pluckStartLine
 	FindFilePathMatchingSearch = findFilePathMatchingSearch
+	FindFuncNames              = findFuncNames
 )
 
 type (
@@ -19,3 +23,33 @@ type (
 func NewFileInfo(name string) fileInfo {
 	return fileInfo{name: name, path: name}
 }
+
+func FindAnnotationsWithMeta(
+	source []byte,
+	forceComment, forceIssue bool,
+	now time.Time,
+) ([]extent, []extent, []extent, []extent, []extent) {
+	fset, node, _ := parseSource(source)
+	a := annotationsFromAST(fset, node, annotationOptions{
+		forceComment: forceComment,
+		forceIssue:   forceIssue,
+		now:          now,
+	})
+
+	return a.valid, a.withoutComment, a.withoutIssue, a.expired, a.invalid
+}
+
+func FindRangeAnnotationsWithMeta(
+	source []byte,
+	forceComment, forceIssue bool,
+	now time.Time,
+) ([]extent, []extent, []extent, []extent, []extent) {
+	fset, node, _ := parseSource(source)
+	a := rangeAnnotationsFromAST(fset, node, annotationOptions{
+		forceComment: forceComment,
+		forceIssue:   forceIssue,
+		now:          now,
+	})
+
+	return a.valid, a.withoutComment, a.withoutIssue, a.expired, a.invalid
+}
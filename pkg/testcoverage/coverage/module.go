@@ -9,6 +9,12 @@ import (
 	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/logger"
 )
 
+// ModulePath returns path of the module which is found in rootDir.
+func ModulePath(rootDir string) string {
+	module, _ := findModuleDirective(defaultRootDir(rootDir))
+	return module
+}
+
 //nolint:nonamedreturns // relax
 func findModuleDirective(rootDir string) (module string, dir string) {
 	logger.L.Debug().Str("root_dir", rootDir).Msg("searching for go.mod")
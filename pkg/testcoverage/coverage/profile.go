@@ -14,9 +14,9 @@ func parseProfiles(paths []string) ([]*cover.Profile, error) {
 	var result []*cover.Profile
 
 	for _, path := range paths {
-		profiles, err := cover.ParseProfiles(path)
+		profiles, err := parseProfile(path)
 		if err != nil {
-			return nil, fmt.Errorf("parsing profile file: %w", err)
+			return nil, err
 		}
 
 		if result == nil {
@@ -37,6 +37,26 @@ func parseProfiles(paths []string) ([]*cover.Profile, error) {
 	return result, nil
 }
 
+// parseProfile parses text profile file, or binary coverage data when path
+// is directory (as written to GOCOVERDIR by binaries built with `-cover`).
+func parseProfile(path string) ([]*cover.Profile, error) {
+	if isCovDataDir(path) {
+		profiles, err := parseCovDataDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("parsing coverage data dir: %w", err)
+		}
+
+		return profiles, nil
+	}
+
+	profiles, err := cover.ParseProfiles(path)
+	if err != nil {
+		return nil, fmt.Errorf("parsing profile file: %w", err)
+	}
+
+	return profiles, nil
+}
+
 func mergeProfiles(a, b []*cover.Profile) ([]*cover.Profile, error) {
 	for _, pb := range b {
 		if idx, found := findProfileForFile(a, pb.FileName); found {
@@ -84,7 +104,11 @@ func mergeSameFileProfile(ap, bp *cover.Profile) (*cover.Profile, error) {
 			b.EndLine == a.EndLine &&
 			b.EndCol == a.EndCol &&
 			b.NumStmt == a.NumStmt {
-			ap.Blocks[i].Count = max(a.Count, b.Count)
+			if hasHitCount(ap.Mode) {
+				ap.Blocks[i].Count = a.Count + b.Count
+			} else {
+				ap.Blocks[i].Count = max(a.Count, b.Count)
+			}
 		} else {
 			logger.L.Debug().
 				Str("a-file", ap.FileName).
@@ -1,9 +1,14 @@
 package coverage_test
 
 import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/tools/cover"
 
 	. "github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/coverage"
 )
@@ -30,17 +35,132 @@ func Test_parseProfiles(t *testing.T) {
 
 	p2, err := ParseProfiles([]string{profileOKFull})
 	assert.NoError(t, err)
-	assert.Equal(t, p1, p2)
+	assert.Equal(t, withoutCounts(p1), withoutCounts(p2))
 
 	p3, err := ParseProfiles([]string{profileOK})
 	assert.NoError(t, err)
 	assert.NotEmpty(t, p3)
 
+	// hit counts are summed, since profiles have `atomic` mode
+	assert.Equal(t, sumCounts(p1), sumCounts(p2)+sumCounts(p3))
+
 	p4, err := ParseProfiles([]string{profileOKNoBadge, profileOK})
 	assert.NoError(t, err)
-	assert.Equal(t, p3, p4)
+	assert.Equal(t, withoutCounts(p3), withoutCounts(p4))
 
 	p5, err := ParseProfiles([]string{profileOK, profileOKNoBadge})
 	assert.NoError(t, err)
 	assert.Equal(t, p4, p5)
 }
+
+func Test_parseProfilesCovData(t *testing.T) {
+	t.Parallel()
+
+	expected, err := ParseProfiles([]string{covDataProfile})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, expected)
+
+	p1, err := ParseProfiles([]string{covDataDir})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, p1)
+
+	// merged with text profile
+	p2, err := ParseProfiles([]string{covDataProfile, covDataDir})
+	assert.NoError(t, err)
+	assert.Equal(t, withoutCounts(expected), withoutCounts(p2))
+	assert.Equal(t, 2*sumCounts(expected), sumCounts(p2))
+
+	// directory without coverage data
+	_, err = ParseProfiles([]string{t.TempDir()})
+	assert.ErrorIs(t, err, ErrInvalidCovData)
+
+	entries, err := os.ReadDir(covDataDir)
+	assert.NoError(t, err)
+
+	copyCovData := func(t *testing.T, corrupt func(name string, data []byte) []byte) string {
+		t.Helper()
+
+		dir := t.TempDir()
+
+		for _, e := range entries {
+			data, err := os.ReadFile(filepath.Join(covDataDir, e.Name()))
+			assert.NoError(t, err)
+			assert.NoError(t, os.WriteFile(filepath.Join(dir, e.Name()), corrupt(e.Name(), data), 0o600))
+		}
+
+		return dir
+	}
+
+	// truncated meta-data file
+	dir := copyCovData(t, func(name string, data []byte) []byte {
+		if strings.HasPrefix(name, "covmeta") {
+			return data[:len(data)/2]
+		}
+
+		return data
+	})
+	_, err = ParseProfiles([]string{dir})
+	assert.ErrorIs(t, err, ErrInvalidCovData)
+
+	// truncated counter file
+	dir = copyCovData(t, func(name string, data []byte) []byte {
+		if strings.HasPrefix(name, "covcounters") {
+			return data[:len(data)/2]
+		}
+
+		return data
+	})
+	_, err = ParseProfiles([]string{dir})
+	assert.ErrorIs(t, err, ErrInvalidCovData)
+
+	// counter file referencing unknown meta-data file
+	dir = copyCovData(t, func(name string, data []byte) []byte {
+		if strings.HasPrefix(name, "covcounters") {
+			data[8] ^= 0xff // first byte of meta-data hash
+		}
+
+		return data
+	})
+	_, err = ParseProfiles([]string{dir})
+	assert.ErrorIs(t, err, ErrInvalidCovData)
+
+	// empty meta-data file
+	dir = copyCovData(t, func(name string, data []byte) []byte {
+		if strings.HasPrefix(name, "covmeta") {
+			return nil
+		}
+
+		return data
+	})
+	_, err = ParseProfiles([]string{dir})
+	assert.ErrorIs(t, err, ErrInvalidCovData)
+}
+
+func withoutCounts(profiles []*cover.Profile) []*cover.Profile {
+	result := make([]*cover.Profile, len(profiles))
+
+	for i, p := range profiles {
+		c := *p
+		c.Blocks = slices.Clone(p.Blocks)
+
+		for j := range c.Blocks {
+			c.Blocks[j].Count = 0
+		}
+
+		result[i] = &c
+	}
+
+	return result
+}
+
+func sumCounts(profiles []*cover.Profile) int {
+	sum := 0
+
+	for _, p := range profiles {
+		for _, b := range p.Blocks {
+			sum += b.Count
+		}
+	}
+
+	return sum
+}
@@ -0,0 +1,86 @@
+package coverage
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Ratchet holds the highest coverage reached by files, packages and in total,
+// which coverage must not fall below. It is stored in the same versioned format
+// as breakdown, where only names and number of statements are kept.
+type Ratchet struct {
+	Header   BreakdownHeader
+	Total    Stats
+	Packages []Stats
+	Files    []Stats
+}
+
+type ratchetJSON struct {
+	Header   breakdownHeaderJSON `json:"header"`
+	Total    statsJSON           `json:"total"`
+	Packages []statsJSON         `json:"packages"`
+	Files    []statsJSON         `json:"files"`
+}
+
+// RatchetSerialize serializes ratchet in versioned (JSON) format.
+func RatchetSerialize(r Ratchet) ([]byte, error) {
+	rj := ratchetJSON{
+		Header:   breakdownHeaderJSON(r.Header),
+		Total:    ratchetStatsToJSON(r.Total),
+		Packages: make([]statsJSON, len(r.Packages)),
+		Files:    make([]statsJSON, len(r.Files)),
+	}
+	rj.Header.Version = BreakdownVersion
+
+	for i, s := range r.Packages {
+		rj.Packages[i] = ratchetStatsToJSON(s)
+	}
+
+	for i, s := range r.Files {
+		rj.Files[i] = ratchetStatsToJSON(s)
+	}
+
+	data, err := json.MarshalIndent(rj, "", "  ")
+	if err != nil { // coverage-ignore
+		return nil, fmt.Errorf("marshal ratchet: %w", err)
+	}
+
+	return append(data, '\n'), nil
+}
+
+// RatchetDeserialize deserializes ratchet from data in versioned (JSON) format.
+func RatchetDeserialize(data []byte) (Ratchet, error) {
+	var rj ratchetJSON
+	if err := json.Unmarshal(data, &rj); err != nil {
+		return Ratchet{}, fmt.Errorf("%w: %w", ErrInvalidFormat, err)
+	}
+
+	if rj.Header.Version < 2 || rj.Header.Version > BreakdownVersion { //nolint:mnd // relax
+		return Ratchet{}, fmt.Errorf("%w: %d", ErrUnsupportedVersion, rj.Header.Version)
+	}
+
+	r := Ratchet{
+		Header:   BreakdownHeader(rj.Header),
+		Total:    ratchetStatsFromJSON(rj.Total),
+		Packages: make([]Stats, len(rj.Packages)),
+		Files:    make([]Stats, len(rj.Files)),
+	}
+
+	for i, s := range rj.Packages {
+		r.Packages[i] = ratchetStatsFromJSON(s)
+	}
+
+	for i, s := range rj.Files {
+		r.Files[i] = ratchetStatsFromJSON(s)
+	}
+
+	return r, nil
+}
+
+func ratchetStatsToJSON(s Stats) statsJSON {
+	return statsJSON{Name: s.Name, Total: s.Total, Covered: s.Covered}
+}
+
+func ratchetStatsFromJSON(s statsJSON) Stats {
+	return Stats{Name: s.Name, Total: s.Total, Covered: s.Covered}
+}
@@ -0,0 +1,50 @@
+package coverage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/coverage"
+)
+
+func TestRatchetSerialization(t *testing.T) {
+	t.Parallel()
+
+	r := Ratchet{
+		Header: BreakdownHeader{
+			ToolVersion: "v2.0.0",
+			Module:      "github.com/foo/bar",
+			Timestamp:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		Total:    Stats{Total: 20, Covered: 17},
+		Packages: []Stats{{Name: "pkg", Total: 20, Covered: 17}},
+		Files: []Stats{
+			{Name: "pkg/foo.go", Total: 11, Covered: 8},
+			{Name: "pkg/bar.go", Total: 9, Covered: 9},
+		},
+	}
+
+	data, err := RatchetSerialize(r)
+	assert.NoError(t, err)
+
+	dr, err := RatchetDeserialize(data)
+	assert.NoError(t, err)
+
+	r.Header.Version = BreakdownVersion
+	assert.Equal(t, r, dr)
+
+	// only names and number of statements are stored
+	r.Files[0].UncoveredLines = []int{1, 2, 3}
+	data, err = RatchetSerialize(r)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "uncovered-lines")
+
+	// invalid formats
+	_, err = RatchetDeserialize([]byte("foo;11;1"))
+	assert.ErrorIs(t, err, ErrInvalidFormat)
+
+	_, err = RatchetDeserialize([]byte(`{"header":{"version":1}}`))
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+}
@@ -13,25 +13,78 @@ import (
 
 type Stats struct {
 	Name                       string
+	Path                       string // path to source file; not stored in breakdown
 	Total                      int64
 	Covered                    int64
-	Threshold                  int
+	Ignored                    int64 // number of statements ignored with annotations
+	Threshold                  float64
+	MaxUncovered               int // maximum number of uncovered statements; 0 means no limit
+	MaxUncoveredLines          int // maximum number of uncovered lines; 0 means no limit
+	CoveredLines               []int
 	UncoveredLines             []int
+	AggregatedUncoveredLines   int   // number of uncovered lines of files aggregated into stats
+	IgnoredLines               []int // lines ignored with coverage-ignore annotations
 	AnnotationsWithoutComments []int
+	UnmatchedAnnotations       []int // lines of unpaired range annotations and misplaced directives
+	ExpiredAnnotations         []int // lines of coverage-ignore with `until` date in the past
+	InvalidAnnotations         []int // lines of coverage-ignore with malformed `until` date
+	AnnotationsWithoutIssue    []int
+	Annotations                []int
+	IgnoredByAnnotation        []AnnotationStats
+	Functions                  []FuncStats
+	Blocks                     []Block // available only for `count` and `atomic` profile modes
+}
+
+// Block holds number of times block of statements was executed.
+type Block struct {
+	StartLine int
+	EndLine   int
+	NumStmt   int
+	Count     int
+}
+
+// AnnotationStats holds number of statements ignored by annotation at line.
+type AnnotationStats struct {
+	Line    int
+	Ignored int64
+}
+
+// FuncStats holds coverage statistics of single function.
+type FuncStats struct {
+	Name      string
+	StartLine int
+	EndLine   int
+	Total     int64
+	Covered   int64
 }
 
 func (s Stats) UncoveredStmtCount() int {
 	return int(s.Total - s.Covered)
 }
 
+// UncoveredLinesCount returns number of uncovered lines. Stats of packages and
+// total do not hold line numbers, only number of uncovered lines of their files.
 func (s Stats) UncoveredLinesCount() int {
-	return len(s.UncoveredLines)
+	return len(s.UncoveredLines) + s.AggregatedUncoveredLines
 }
 
 func (s Stats) CoveredPercentage() int {
 	return CoveredPercentage(s.Total, s.Covered)
 }
 
+// IgnoredPercentage returns percentage of statements ignored with annotations
+// out of all statements, including ignored ones. Percentage is not rounded, so
+// it can be compared with limits.
+func (s Stats) IgnoredPercentage() float64 {
+	return coveredPercentageF(s.Total+s.Ignored, s.Ignored, false)
+}
+
+// IgnoredPercentageFloor returns percentage of ignored statements rounded down
+// to one decimal, which is used when it is displayed.
+func (s Stats) IgnoredPercentageFloor() float64 {
+	return percentageFloor(s.Total+s.Ignored, s.Ignored)
+}
+
 func (s Stats) CoveredPercentageF() float64 {
 	return coveredPercentageF(s.Total, s.Covered, true)
 }
@@ -40,17 +93,62 @@ func (s Stats) CoveredPercentageFNR() float64 {
 	return coveredPercentageF(s.Total, s.Covered, false)
 }
 
+// CoveredPercentageFloor returns coverage percentage rounded down to one decimal,
+// which is used when coverage is displayed.
+func (s Stats) CoveredPercentageFloor() float64 {
+	return percentageFloor(s.Total, s.Covered)
+}
+
+// Str returns coverage percentage with number of covered and total statements.
+// Percentage is rounded down to one decimal, so that coverage below threshold is
+// never displayed as equal to threshold (eg. 79.96% is displayed as 79.9%).
+//
 //nolint:mnd // relax
 func (s Stats) Str() string {
-	c := s.CoveredPercentage()
+	p := s.CoveredPercentageFloor()
 
-	if c == 100 { // precision not needed
-		return fmt.Sprintf("%d%% (%d/%d)", c, s.Covered, s.Total)
-	} else if c < 10 { // adds space for single digit number
-		return fmt.Sprintf(" %.1f%% (%d/%d)", s.CoveredPercentageF(), s.Covered, s.Total)
+	if p == 100 { // precision not needed
+		return fmt.Sprintf("100%% (%d/%d)", s.Covered, s.Total)
+	} else if p < 10 { // adds space for single digit number
+		return fmt.Sprintf(" %.1f%% (%d/%d)", p, s.Covered, s.Total)
 	}
 
-	return fmt.Sprintf("%.1f%% (%d/%d)", s.CoveredPercentageF(), s.Covered, s.Total)
+	return fmt.Sprintf("%.1f%% (%d/%d)", p, s.Covered, s.Total)
+}
+
+// LineHit holds number of times line was executed.
+type LineHit struct {
+	Line int
+	Hits int
+}
+
+// LineHits returns number of times each line with statements was executed, sorted
+// by line number. Uncovered lines have zero hits. When stats do not have hit counts
+// (profile with `set` mode) covered lines have one hit.
+func (s Stats) LineHits() []LineHit {
+	lines := slices.Concat(s.CoveredLines, s.UncoveredLines)
+	slices.Sort(lines)
+	lines = slices.Compact(lines)
+
+	res := make([]LineHit, 0, len(lines))
+
+	for _, l := range lines {
+		hits := 0
+
+		if !slices.Contains(s.UncoveredLines, l) {
+			hits = 1
+
+			for _, b := range s.Blocks {
+				if b.StartLine <= l && l <= b.EndLine {
+					hits = max(hits, b.Count)
+				}
+			}
+		}
+
+		res = append(res, LineHit{Line: l, Hits: hits})
+	}
+
+	return res
 }
 
 func SortStatsByName(stats []Stats) {
@@ -72,6 +170,15 @@ func CoveredPercentage(total, covered int64) int {
 	return int(coveredPercentageF(total, covered, true))
 }
 
+//nolint:mnd // relax
+func percentageFloor(total, part int64) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return float64(part*1000/total) / 10
+}
+
 //nolint:mnd // relax
 func coveredPercentageF(total, covered int64, round bool) float64 {
 	if total == 0 {
@@ -134,11 +241,33 @@ func StatsCalcTotal(stats []Stats) Stats {
 	for _, s := range stats {
 		total.Total += s.Total
 		total.Covered += s.Covered
+		total.Ignored += s.Ignored
+		total.AggregatedUncoveredLines += s.UncoveredLinesCount()
 	}
 
 	return total
 }
 
+// StatsFunctions returns statistics of all functions, where function name is
+// prefixed with file name in format `file.go:Func`. Statistics of function hold
+// its FuncStats, which has position of function in file.
+func StatsFunctions(stats []Stats) []Stats {
+	var result []Stats
+
+	for _, s := range stats {
+		for _, f := range s.Functions {
+			result = append(result, Stats{
+				Name:      s.Name + ":" + f.Name,
+				Total:     f.Total,
+				Covered:   f.Covered,
+				Functions: []FuncStats{f},
+			})
+		}
+	}
+
+	return result
+}
+
 func StatsPluckName(stats []Stats) []string {
 	result := make([]string, len(stats))
 
@@ -155,6 +284,30 @@ func StatsFilterWithUncoveredLines(stats []Stats) []Stats {
 	})
 }
 
+// StatsColdBlocks returns stats of files which have cold blocks, which are blocks
+// that were executed at least once, but fewer than minHits times. Blocks of returned
+// stats hold only cold blocks.
+func StatsColdBlocks(stats []Stats, minHits int) []Stats {
+	var res []Stats
+
+	for _, s := range stats {
+		var cold []Block
+
+		for _, b := range s.Blocks {
+			if b.Count > 0 && b.Count < minHits {
+				cold = append(cold, b)
+			}
+		}
+
+		if len(cold) > 0 {
+			s.Blocks = cold
+			res = append(res, s)
+		}
+	}
+
+	return res
+}
+
 func StatsFilterWithCoveredLines(stats []Stats) []Stats {
 	return filter(stats, func(s Stats) bool {
 		return len(s.UncoveredLines) == 0
@@ -168,6 +321,36 @@ func StatsFilterWithMissingExplanations(stats []Stats) []Stats {
 	})
 }
 
+// StatsFilterWithUnmatchedAnnotations returns stats that have unmatched range annotations
+// or misplaced directives
+func StatsFilterWithUnmatchedAnnotations(stats []Stats) []Stats {
+	return filter(stats, func(s Stats) bool {
+		return len(s.UnmatchedAnnotations) > 0
+	})
+}
+
+// StatsFilterWithExpiredAnnotations returns stats that have expired annotations
+func StatsFilterWithExpiredAnnotations(stats []Stats) []Stats {
+	return filter(stats, func(s Stats) bool {
+		return len(s.ExpiredAnnotations) > 0
+	})
+}
+
+// StatsFilterWithInvalidAnnotations returns stats that have annotations with invalid
+// `until` date
+func StatsFilterWithInvalidAnnotations(stats []Stats) []Stats {
+	return filter(stats, func(s Stats) bool {
+		return len(s.InvalidAnnotations) > 0
+	})
+}
+
+// StatsFilterWithMissingIssues returns stats that have annotations without issue reference
+func StatsFilterWithMissingIssues(stats []Stats) []Stats {
+	return filter(stats, func(s Stats) bool {
+		return len(s.AnnotationsWithoutIssue) > 0
+	})
+}
+
 func filter[T any](slice []T, predicate func(T) bool) []T {
 	var result []T
 
@@ -180,6 +363,11 @@ func filter[T any](slice []T, predicate func(T) bool) []T {
 	return result
 }
 
+// StatsSerialize serializes stats in legacy breakdown format, which holds only
+// names and numbers of statements.
+//
+// Deprecated: use BreakdownSerialize, which also holds line-level data needed
+// for coverage difference. Legacy format can still be deserialized.
 func StatsSerialize(stats []Stats) []byte {
 	b := bytes.Buffer{}
 	sep, nl := []byte(";"), []byte("\n")
@@ -199,7 +387,18 @@ func StatsSerialize(stats []Stats) []byte {
 
 var ErrInvalidFormat = errors.New("invalid format")
 
+// StatsDeserialize deserializes stats from breakdown file data, supporting both
+// legacy and versioned breakdown format.
 func StatsDeserialize(b []byte) ([]Stats, error) {
+	bd, err := BreakdownDeserialize(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return bd.Stats, nil
+}
+
+func statsDeserializeLegacy(b []byte) ([]Stats, error) {
 	deserializeLine := func(bl []byte) (Stats, error) {
 		fields := bytes.Split(bl, []byte(";"))
 		if len(fields) != 3 { //nolint:mnd // relax
@@ -30,14 +30,27 @@ func TestCoveredPercentage(t *testing.T) {
 	}
 }
 
+func TestStatsCoveredPercentageFloor(t *testing.T) {
+	t.Parallel()
+
+	assert.InDelta(t, 0, Stats{}.CoveredPercentageFloor(), 0)
+	assert.InDelta(t, 66.6, Stats{Covered: 2, Total: 3}.CoveredPercentageFloor(), 0)
+	assert.InDelta(t, 99.9, Stats{Covered: 9999, Total: 10000}.CoveredPercentageFloor(), 0)
+	assert.InDelta(t, 100, Stats{Covered: 3, Total: 3}.CoveredPercentageFloor(), 0)
+}
+
 func TestStatStr(t *testing.T) {
 	t.Parallel()
 
 	assert.Equal(t, " 0.0% (0/0)", Stats{}.Str())
-	assert.Equal(t, " 9.1% (1/11)", Stats{Covered: 1, Total: 11}.Str())
+	assert.Equal(t, " 9.0% (1/11)", Stats{Covered: 1, Total: 11}.Str())
 	assert.Equal(t, "22.2% (2/9)", Stats{Covered: 2, Total: 9}.Str())
-	assert.Equal(t, "73.0% (200/274)", Stats{Covered: 200, Total: 274}.Str())
+	assert.Equal(t, "72.9% (200/274)", Stats{Covered: 200, Total: 274}.Str())
 	assert.Equal(t, "100% (10/10)", Stats{Covered: 10, Total: 10}.Str())
+
+	// percentage is rounded down, so coverage below threshold is not displayed as threshold
+	assert.Equal(t, "79.9% (7996/10000)", Stats{Covered: 7996, Total: 10000}.Str())
+	assert.Equal(t, "99.9% (2499/2500)", Stats{Covered: 2499, Total: 2500}.Str())
 }
 
 func TestStatsSerialization(t *testing.T) {
@@ -48,7 +61,7 @@ func TestStatsSerialization(t *testing.T) {
 		{Name: "bar", Total: 9, Covered: 2},
 	}
 
-	b := StatsSerialize(stats)
+	b := StatsSerialize(stats) //nolint:staticcheck // legacy format is still supported
 	assert.Equal(t, "foo;11;1\nbar;9;2\n", string(b))
 
 	ds, err := StatsDeserialize(b)
@@ -70,3 +83,62 @@ func TestStatsSerialization(t *testing.T) {
 	_, err = StatsDeserialize([]byte("foo;"))
 	assert.Error(t, err)
 }
+
+func TestStatsLineHits(t *testing.T) {
+	t.Parallel()
+
+	// stats without hit counts
+	s := Stats{CoveredLines: []int{1, 2, 3}, UncoveredLines: []int{3, 4}}
+	assert.Equal(t, []LineHit{{1, 1}, {2, 1}, {3, 0}, {4, 0}}, s.LineHits())
+
+	// stats with hit counts
+	s.Blocks = []Block{
+		{StartLine: 1, EndLine: 2, Count: 3},
+		{StartLine: 2, EndLine: 3, Count: 7},
+		{StartLine: 3, EndLine: 4, Count: 0},
+	}
+	assert.Equal(t, []LineHit{{1, 3}, {2, 7}, {3, 0}, {4, 0}}, s.LineHits())
+
+	assert.Empty(t, Stats{}.LineHits())
+}
+
+func TestStatsColdBlocks(t *testing.T) {
+	t.Parallel()
+
+	stats := []Stats{
+		{Name: "foo.go", Blocks: []Block{
+			{StartLine: 1, EndLine: 2, Count: 0},
+			{StartLine: 3, EndLine: 4, Count: 1},
+			{StartLine: 5, EndLine: 6, Count: 5},
+		}},
+		{Name: "bar.go", Blocks: []Block{{StartLine: 1, EndLine: 2, Count: 10}}},
+	}
+
+	assert.Empty(t, StatsColdBlocks(stats, 0))
+	assert.Empty(t, StatsColdBlocks(stats, 1))
+
+	cold := StatsColdBlocks(stats, 5)
+	assert.Len(t, cold, 1)
+	assert.Equal(t, "foo.go", cold[0].Name)
+	assert.Equal(t, []Block{{StartLine: 3, EndLine: 4, Count: 1}}, cold[0].Blocks)
+
+	assert.Len(t, StatsColdBlocks(stats, 11), 2)
+}
+
+func TestStatsFunctions(t *testing.T) {
+	t.Parallel()
+
+	stats := []Stats{
+		{Name: "foo.go", Functions: []FuncStats{
+			{Name: "Foo", StartLine: 1, EndLine: 3, Total: 2, Covered: 1},
+			{Name: "(*T).Bar", StartLine: 5, EndLine: 9, Total: 3, Covered: 3},
+		}},
+		{Name: "bar.go"},
+	}
+
+	assert.Equal(t, []Stats{
+		{Name: "foo.go:Foo", Total: 2, Covered: 1, Functions: stats[0].Functions[:1]},
+		{Name: "foo.go:(*T).Bar", Total: 3, Covered: 3, Functions: stats[0].Functions[1:]},
+	}, StatsFunctions(stats))
+	assert.Empty(t, StatsFunctions(nil))
+}
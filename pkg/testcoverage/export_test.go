@@ -6,6 +6,10 @@ const (
 	GaOutputBadgeColor    = gaOutputBadgeColor
 	GaOutputBadgeText     = gaOutputBadgeText
 	GaOutputReport        = gaOutputReport
+	GaStepSummaryEnv      = gaStepSummaryEnv
+	GlOutputDirEnv        = glOutputDirEnv
+	GlCodeQualityFile     = glCodeQualityFile
+	GlDotenvFile          = glDotenvFile
 )
 
 var (
@@ -18,8 +22,10 @@ var (
 	CompressUncoveredLines    = compressUncoveredLines
 	ReportUncoveredLines      = reportUncoveredLines
 	StatusStr                 = statusStr
+	MakeJSONReport            = makeJSONReport
 )
 
 type (
 	StorerFactories = storerFactories
+	JSONReport      = jsonReport
 )
@@ -311,3 +311,22 @@ func readStats(t *testing.T, file string) []coverage.Stats {
 
 	return stats
 }
+
+func readRatchet(t *testing.T, file string) coverage.Ratchet {
+	t.Helper()
+
+	contentBytes, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	r, err := coverage.RatchetDeserialize(contentBytes)
+	assert.NoError(t, err)
+
+	return r
+}
+
+func writeRatchet(t *testing.T, file string, r coverage.Ratchet) {
+	t.Helper()
+
+	data, err := coverage.RatchetSerialize(r)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(file, data, 0o600))
+}
@@ -0,0 +1,192 @@
+package patch
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidHunk = errors.New("invalid hunk header")
+
+// ChangedLines holds line numbers (in the new version of the file) that were
+// added or modified, keyed by file path as it appears in the unified diff.
+type ChangedLines map[string][]int
+
+// ForFile returns changed lines for the file with given name. Since paths in
+// diff are relative to repository root, and file names used by the tool are
+// relative to the module root, file is matched by path suffix.
+func (c ChangedLines) ForFile(name string) ([]int, bool) {
+	if lines, ok := c[name]; ok {
+		return lines, true
+	}
+
+	for file, lines := range c {
+		if strings.HasSuffix(file, "/"+name) {
+			return lines, true
+		}
+	}
+
+	return nil, false
+}
+
+// FromFile reads unified diff from file and returns lines changed by it.
+func FromFile(filename string) (ChangedLines, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading patch file: %w", err)
+	}
+
+	return Parse(data)
+}
+
+// FromGit returns lines changed between base revision and HEAD, as reported
+// by `git diff base...HEAD` executed in dir.
+func FromGit(dir, base string) (ChangedLines, error) {
+	//nolint:gosec // base is user provided git revision
+	cmd := exec.Command("git", "diff", "--unified=0", "--no-color", "--no-ext-diff", base+"...HEAD")
+	cmd.Dir = dir
+
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("running git diff: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return Parse(out)
+}
+
+// Parse parses unified diff and returns lines added or modified for each file.
+// Deleted files are omitted from the result.
+func Parse(data []byte) (ChangedLines, error) {
+	result := make(ChangedLines)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 1024*1024) //nolint:mnd // allow long lines
+
+	var (
+		file string
+		h    hunk
+	)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if !h.inProgress() {
+			switch {
+			case strings.HasPrefix(line, "+++ "):
+				file = parseFileName(strings.TrimPrefix(line, "+++ "))
+			case strings.HasPrefix(line, "@@"):
+				var err error
+				if h, err = parseHunkHeader(line); err != nil {
+					return nil, err
+				}
+			}
+
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "+"):
+			if file != "" {
+				result[file] = append(result[file], h.newLine)
+			}
+
+			h.newLine++
+			h.newRemaining--
+		case strings.HasPrefix(line, "-"):
+			h.oldRemaining--
+		case strings.HasPrefix(line, " "), line == "":
+			h.newLine++
+			h.newRemaining--
+			h.oldRemaining--
+		}
+	}
+
+	if err := scanner.Err(); err != nil { // coverage-ignore
+		return nil, fmt.Errorf("reading patch: %w", err)
+	}
+
+	for file := range result {
+		slices.Sort(result[file])
+	}
+
+	return result, nil
+}
+
+type hunk struct {
+	newLine      int
+	oldRemaining int
+	newRemaining int
+}
+
+func (h hunk) inProgress() bool {
+	return h.oldRemaining > 0 || h.newRemaining > 0
+}
+
+func parseFileName(s string) string {
+	// file name may be followed by tab and timestamp
+	if i := strings.IndexByte(s, '\t'); i != -1 {
+		s = s[:i]
+	}
+
+	s = strings.Trim(strings.TrimSpace(s), `"`)
+	if s == "/dev/null" {
+		return ""
+	}
+
+	s, _ = strings.CutPrefix(s, "b/")
+
+	return s
+}
+
+// parseHunkHeader parses hunk header in format `@@ -l,s +l,s @@ optional heading`.
+func parseHunkHeader(line string) (hunk, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 || //nolint:mnd // relax
+		!strings.HasPrefix(fields[1], "-") ||
+		!strings.HasPrefix(fields[2], "+") {
+		return hunk{}, fmt.Errorf("%w: %q", ErrInvalidHunk, line)
+	}
+
+	_, oldCount, errOld := parseRange(fields[1][1:])
+	newStart, newCount, errNew := parseRange(fields[2][1:])
+
+	if errOld != nil || errNew != nil {
+		return hunk{}, fmt.Errorf("%w: %q", ErrInvalidHunk, line)
+	}
+
+	return hunk{
+		newLine:      newStart,
+		oldRemaining: oldCount,
+		newRemaining: newCount,
+	}, nil
+}
+
+// parseRange parses range in format `start,count`, where count
+// is optional and defaults to 1.
+func parseRange(s string) (int, int, error) {
+	startStr, countStr, hasCount := strings.Cut(s, ",")
+
+	start, err := strconv.Atoi(startStr)
+	if err != nil {
+		return 0, 0, err //nolint:wrapcheck // error is wrapped at level above
+	}
+
+	if !hasCount {
+		return start, 1, nil
+	}
+
+	count, err := strconv.Atoi(countStr)
+	if err != nil {
+		return 0, 0, err //nolint:wrapcheck // error is wrapped at level above
+	}
+
+	return start, count, nil
+}
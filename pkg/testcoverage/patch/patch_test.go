@@ -0,0 +1,120 @@
+package patch_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/patch"
+)
+
+const diff = `diff --git a/pkg/foo.go b/pkg/foo.go
+index 1111111..2222222 100644
+--- a/pkg/foo.go
++++ b/pkg/foo.go
+@@ -3,0 +4,2 @@ func foo() {
++	a := 1
++	b := 2
+@@ -10,3 +12,3 @@ func bar() {
+ 	x := 0
+-	y := 0
++	y := 1
+ 	return x + y
+diff --git a/pkg/removed.go b/pkg/removed.go
+deleted file mode 100644
+--- a/pkg/removed.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package pkg
+-
+diff --git a/pkg/new.go b/pkg/new.go
+new file mode 100644
+--- /dev/null
++++ b/pkg/new.go
+@@ -0,0 +1,3 @@
++package pkg
++
++--- not a header
+`
+
+func Test_Parse(t *testing.T) {
+	t.Parallel()
+
+	lines, err := Parse([]byte(diff))
+	assert.NoError(t, err)
+	assert.Equal(t, ChangedLines{
+		"pkg/foo.go": {4, 5, 13},
+		"pkg/new.go": {1, 2, 3},
+	}, lines)
+
+	lines, err = Parse(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, lines)
+
+	_, err = Parse([]byte("+++ b/foo.go\n@@ -1 +x @@\n"))
+	assert.ErrorIs(t, err, ErrInvalidHunk)
+
+	_, err = Parse([]byte("+++ b/foo.go\n@@ -1,x +1 @@\n"))
+	assert.ErrorIs(t, err, ErrInvalidHunk)
+
+	_, err = Parse([]byte("+++ b/foo.go\n@@ -1 @@\n"))
+	assert.ErrorIs(t, err, ErrInvalidHunk)
+
+	// hunk without counts, and file name with timestamp
+	lines, err = Parse([]byte("--- foo.go\t2024-01-01\n+++ foo.go\t2024-01-02\n@@ -1 +1 @@\n-a\n+b\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, ChangedLines{"foo.go": {1}}, lines)
+}
+
+func Test_ChangedLines_ForFile(t *testing.T) {
+	t.Parallel()
+
+	lines := ChangedLines{
+		"project/pkg/foo.go": {1},
+		"bar.go":             {2},
+	}
+
+	l, ok := lines.ForFile("pkg/foo.go")
+	assert.True(t, ok)
+	assert.Equal(t, []int{1}, l)
+
+	l, ok = lines.ForFile("bar.go")
+	assert.True(t, ok)
+	assert.Equal(t, []int{2}, l)
+
+	_, ok = lines.ForFile("foo.go/pkg")
+	assert.False(t, ok)
+
+	_, ok = lines.ForFile("o.go")
+	assert.False(t, ok)
+}
+
+func Test_FromFile(t *testing.T) {
+	t.Parallel()
+
+	_, err := FromFile(t.TempDir())
+	assert.Error(t, err)
+
+	file := t.TempDir() + "/changes.patch"
+	assert.NoError(t, os.WriteFile(file, []byte(diff), 0o600))
+
+	lines, err := FromFile(file)
+	assert.NoError(t, err)
+	assert.Len(t, lines, 2)
+}
+
+func Test_FromGit(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		return
+	}
+
+	_, err := FromGit(t.TempDir(), "HEAD")
+	assert.Error(t, err)
+
+	lines, err := FromGit("../../../", "HEAD")
+	assert.NoError(t, err)
+	assert.Empty(t, lines)
+}
@@ -0,0 +1,111 @@
+package prcomment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v88/github"
+)
+
+// Marker is hidden in body of the comment so the same comment can be found
+// and updated on subsequent runs.
+const Marker = "<!-- go-test-coverage:pr-comment -->"
+
+const listPerPage = 100
+
+type Github struct {
+	Token       string
+	Owner       string
+	Repository  string
+	PullRequest int
+
+	// BaseURL of GitHub API, default is used when it is not set.
+	BaseURL string
+}
+
+// Upsert creates comment with body on pull request, or updates existing comment
+// made earlier when there is one. It returns true when comment has changed.
+func Upsert(cfg Github, body string) (bool, error) {
+	client, err := newClient(cfg)
+	if err != nil {
+		return false, err
+	}
+
+	ctx := context.Background()
+	body = Marker + "\n" + body
+
+	existing, err := findComment(ctx, client, cfg)
+	if err != nil {
+		return false, err
+	}
+
+	if existing == nil {
+		_, _, err = client.Issues.CreateComment(ctx, cfg.Owner, cfg.Repository, cfg.PullRequest,
+			&github.IssueComment{Body: &body},
+		)
+		if err != nil {
+			return false, fmt.Errorf("create comment: %w", err)
+		}
+
+		return true, nil
+	}
+
+	if existing.GetBody() == body { // same comment already exists... do nothing
+		return false, nil
+	}
+
+	_, _, err = client.Issues.EditComment(ctx, cfg.Owner, cfg.Repository, existing.GetID(),
+		&github.IssueComment{Body: &body},
+	)
+	if err != nil {
+		return false, fmt.Errorf("update comment: %w", err)
+	}
+
+	return true, nil
+}
+
+func newClient(cfg Github) (*github.Client, error) {
+	opts := []github.ClientOptionsFunc{github.WithAuthToken(cfg.Token)}
+	if cfg.BaseURL != "" {
+		opts = append(opts, github.WithURLs(&cfg.BaseURL, nil))
+	}
+
+	client, err := github.NewClient(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("create github client: %w", err)
+	}
+
+	return client, nil
+}
+
+func findComment(
+	ctx context.Context,
+	client *github.Client,
+	cfg Github,
+) (*github.IssueComment, error) {
+	opts := &github.IssueListCommentsOptions{
+		ListOptions: github.ListOptions{PerPage: listPerPage},
+	}
+
+	for {
+		comments, resp, err := client.Issues.ListComments(
+			ctx, cfg.Owner, cfg.Repository, cfg.PullRequest, opts,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("list comments: %w", err)
+		}
+
+		for _, c := range comments {
+			if strings.HasPrefix(c.GetBody(), Marker) {
+				return c, nil
+			}
+		}
+
+		if resp.NextPage == 0 {
+			return nil, nil
+		}
+
+		opts.Page = resp.NextPage
+	}
+}
@@ -0,0 +1,251 @@
+package prcomment_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/prcomment"
+)
+
+func Test_Upsert(t *testing.T) {
+	t.Parallel()
+
+	srv := newGithubServer(t)
+	cfg := srv.config()
+
+	// comment is created when it does not exist
+	changed, err := Upsert(cfg, "report 1")
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, []string{Marker + "\nreport 1"}, srv.bodies())
+
+	// same comment is not updated
+	changed, err = Upsert(cfg, "report 1")
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, []string{Marker + "\nreport 1"}, srv.bodies())
+
+	// existing comment is updated
+	changed, err = Upsert(cfg, "report 2")
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, []string{Marker + "\nreport 2"}, srv.bodies())
+}
+
+func Test_Upsert_Paginated(t *testing.T) {
+	t.Parallel()
+
+	srv := newGithubServer(t)
+	cfg := srv.config()
+
+	// fill more than one page with comments made by others
+	for i := range 150 {
+		srv.addComment("comment " + strconv.Itoa(i))
+	}
+
+	changed, err := Upsert(cfg, "report 1")
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Len(t, srv.bodies(), 151)
+
+	changed, err = Upsert(cfg, "report 2")
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	bodies := srv.bodies()
+	assert.Len(t, bodies, 151)
+	assert.Equal(t, Marker+"\nreport 2", bodies[150])
+	assert.Equal(t, "comment 0", bodies[0])
+}
+
+func Test_Upsert_Error(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid base url", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := Github{Token: "token", Owner: "owner", Repository: "repo", PullRequest: 1}
+		cfg.BaseURL = "://invalid"
+
+		changed, err := Upsert(cfg, "report")
+		assert.Error(t, err)
+		assert.False(t, changed)
+	})
+
+	t.Run("list comments", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newGithubServer(t)
+		srv.failOn = http.MethodGet
+
+		changed, err := Upsert(srv.config(), "report")
+		assert.ErrorContains(t, err, "list comments")
+		assert.False(t, changed)
+	})
+
+	t.Run("create comment", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newGithubServer(t)
+		srv.failOn = http.MethodPost
+
+		changed, err := Upsert(srv.config(), "report")
+		assert.ErrorContains(t, err, "create comment")
+		assert.False(t, changed)
+	})
+
+	t.Run("update comment", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newGithubServer(t)
+		srv.failOn = http.MethodPatch
+		srv.addComment(Marker + "\nreport")
+
+		changed, err := Upsert(srv.config(), "new report")
+		assert.ErrorContains(t, err, "update comment")
+		assert.False(t, changed)
+	})
+}
+
+type comment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+// githubServer is minimal stand-in for GitHub API which handles
+// issue comments of single pull request.
+type githubServer struct {
+	*httptest.Server
+
+	mx       sync.Mutex
+	comments []comment
+	failOn   string
+}
+
+const (
+	owner       = "owner"
+	repository  = "repo"
+	pullRequest = 7
+	perPage     = 100
+)
+
+func newGithubServer(t *testing.T) *githubServer {
+	t.Helper()
+
+	s := &githubServer{}
+
+	mux := http.NewServeMux()
+	issuePath := fmt.Sprintf("/repos/%s/%s/issues/%d/comments", owner, repository, pullRequest)
+	mux.HandleFunc("GET "+issuePath, s.list)
+	mux.HandleFunc("POST "+issuePath, s.create)
+	mux.HandleFunc(fmt.Sprintf("PATCH /repos/%s/%s/issues/comments/{id}", owner, repository), s.edit)
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == s.failOn {
+			http.Error(w, `{"message":"failure"}`, http.StatusInternalServerError)
+			return
+		}
+
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *githubServer) config() Github {
+	return Github{
+		Token:       "token",
+		Owner:       owner,
+		Repository:  repository,
+		PullRequest: pullRequest,
+		BaseURL:     s.URL,
+	}
+}
+
+func (s *githubServer) addComment(body string) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.comments = append(s.comments, comment{ID: int64(len(s.comments) + 1), Body: body})
+}
+
+func (s *githubServer) bodies() []string {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	res := make([]string, len(s.comments))
+	for i, c := range s.comments {
+		res[i] = c.Body
+	}
+
+	return res
+}
+
+func (s *githubServer) list(w http.ResponseWriter, r *http.Request) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	page = max(page, 1)
+
+	from := min((page-1)*perPage, len(s.comments))
+	to := min(page*perPage, len(s.comments))
+
+	if to < len(s.comments) {
+		w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=%d>; rel="next"`, s.URL, r.URL.Path, page+1))
+	}
+
+	writeJSON(w, s.comments[from:to])
+}
+
+func (s *githubServer) create(w http.ResponseWriter, r *http.Request) {
+	var c comment
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.addComment(c.Body)
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	writeJSON(w, s.comments[len(s.comments)-1])
+}
+
+func (s *githubServer) edit(w http.ResponseWriter, r *http.Request) {
+	var c comment
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	for i := range s.comments {
+		if s.comments[i].ID == id {
+			s.comments[i].Body = c.Body
+			writeJSON(w, s.comments[i])
+
+			return
+		}
+	}
+
+	http.NotFound(w, r)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v) //nolint:errcheck,errchkjson // relax
+}
@@ -0,0 +1,134 @@
+package testcoverage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/coverage"
+)
+
+const ratchetTotalName = "total"
+
+// RatchetRegression holds stats of file, package or total whose coverage
+// dropped below coverage stored in ratchet file.
+type RatchetRegression struct {
+	Current coverage.Stats
+	Stored  coverage.Stats
+}
+
+func loadRatchet(cfg Config) (*coverage.Ratchet, error) {
+	if cfg.Ratchet.FileName == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(cfg.Ratchet.FileName)
+	if errors.Is(err, fs.ErrNotExist) && cfg.Ratchet.Update {
+		return &coverage.Ratchet{}, nil // ratchet file is created on first update
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading file content failed: %w", err)
+	}
+
+	r, err := coverage.RatchetDeserialize(data)
+	if err != nil {
+		return nil, fmt.Errorf("deserializing ratchet file failed: %w", err)
+	}
+
+	return &r, nil
+}
+
+func saveRatchet(cfg Config, r coverage.Ratchet) error {
+	data, err := coverage.RatchetSerialize(r)
+	if err != nil { // coverage-ignore
+		return fmt.Errorf("serializing ratchet failed: %w", err)
+	}
+
+	//nolint:mnd,wrapcheck,gosec // relax
+	return os.WriteFile(cfg.Ratchet.FileName, data, 0o644)
+}
+
+// checkRatchet returns files, packages and total whose coverage dropped below
+// coverage stored in ratchet by more than tolerance. Entries which are not
+// stored in ratchet (new or renamed files) are not checked.
+func checkRatchet(
+	r coverage.Ratchet,
+	result AnalyzeResult,
+	tolerance float64,
+) []RatchetRegression {
+	var res []RatchetRegression
+
+	res = append(res, ratchetRegressions(result.Files, r.Files, tolerance)...)
+	res = append(res, ratchetRegressions(result.Packages, r.Packages, tolerance)...)
+
+	total := result.TotalStats
+	total.Name = ratchetTotalName
+
+	stored := r.Total
+	stored.Name = ratchetTotalName
+
+	res = append(res, ratchetRegressions(
+		[]coverage.Stats{total}, []coverage.Stats{stored}, tolerance,
+	)...)
+
+	return res
+}
+
+func ratchetRegressions(
+	current, stored []coverage.Stats,
+	tolerance float64,
+) []RatchetRegression {
+	var res []RatchetRegression
+
+	storedSearchMap := coverage.StatsSearchMap(stored)
+
+	for _, s := range current {
+		st, found := storedSearchMap[s.Name]
+		if !found || st.Total == 0 || s.Total == 0 {
+			continue
+		}
+
+		if s.CoveredPercentageFNR() < st.CoveredPercentageFNR()-tolerance {
+			res = append(res, RatchetRegression{Current: s, Stored: st})
+		}
+	}
+
+	return res
+}
+
+// raiseRatchet returns ratchet with current stats of files, packages and total,
+// where coverage is never lower than coverage stored in ratchet. Entries which
+// no longer exist (deleted or renamed files) are dropped.
+func raiseRatchet(
+	header coverage.BreakdownHeader,
+	r coverage.Ratchet,
+	result AnalyzeResult,
+) coverage.Ratchet {
+	return coverage.Ratchet{
+		Header:   header,
+		Total:    raiseStats([]coverage.Stats{result.TotalStats}, []coverage.Stats{r.Total})[0],
+		Packages: raiseStats(result.Packages, r.Packages),
+		Files:    raiseStats(result.Files, r.Files),
+	}
+}
+
+func raiseStats(current, stored []coverage.Stats) []coverage.Stats {
+	res := make([]coverage.Stats, len(current))
+
+	storedSearchMap := coverage.StatsSearchMap(stored)
+
+	for i, s := range current {
+		res[i] = coverage.Stats{Name: s.Name, Total: s.Total, Covered: s.Covered}
+
+		st, found := storedSearchMap[s.Name]
+		if found && st.Total > 0 && st.CoveredPercentageFNR() > s.CoveredPercentageFNR() {
+			res[i] = coverage.Stats{Name: st.Name, Total: st.Total, Covered: st.Covered}
+		}
+	}
+
+	coverage.SortStatsByName(res)
+
+	return res
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"text/tabwriter"
@@ -22,7 +23,15 @@ func ReportForHuman(w io.Writer, result AnalyzeResult) {
 	reportCoverage(out, result)
 	reportUncoveredLines(out, result)
 	reportMissingExplanations(out, result)
+	reportUnmatchedAnnotations(out, result)
+	reportExpiredAnnotations(out, result)
+	reportInvalidAnnotations(out, result)
+	reportMissingIssues(out, result)
 	reportDiff(out, result)
+	reportNewCode(out, result)
+	reportColdBlocks(out, result)
+	reportIgnoredStatements(out, result)
+	reportExcludedBySource(out, result)
 }
 
 func reportCoverage(w io.Writer, result AnalyzeResult) {
@@ -30,41 +39,217 @@ func reportCoverage(w io.Writer, result AnalyzeResult) {
 	defer tabber.Flush()
 
 	thr := result.Threshold
+	mu := result.MaxUncovered
+	mul := result.MaxUncoveredLines
 
-	if thr.File > 0 || result.HasFileOverrides { // File threshold report
-		fmt.Fprintf(tabber, "File coverage threshold (%d%%) satisfied:\t", thr.File)
+	if result.HasFileThreshold() { // File threshold report
+		fmt.Fprintf(tabber, "File coverage threshold (%s) satisfied:\t",
+			thresholdStr(thr.File, mu.File, mul.File))
 		fmt.Fprint(tabber, statusStr(len(result.FilesBelowThreshold) == 0))
 		reportIssuesForHuman(tabber, result.FilesBelowThreshold)
 		fmt.Fprint(tabber, "\n")
 	}
 
-	if thr.Package > 0 || result.HasPackageOverrides { // Package threshold report
-		fmt.Fprintf(tabber, "Package coverage threshold (%d%%) satisfied:\t", thr.Package)
+	if result.HasPackageThreshold() { // Package threshold report
+		fmt.Fprintf(tabber, "Package coverage threshold (%s) satisfied:\t",
+			thresholdStr(thr.Package, mu.Package, mul.Package))
 		fmt.Fprint(tabber, statusStr(len(result.PackagesBelowThreshold) == 0))
 		reportIssuesForHuman(tabber, result.PackagesBelowThreshold)
 		fmt.Fprint(tabber, "\n")
 	}
 
-	if thr.Total > 0 { // Total threshold report
-		fmt.Fprintf(tabber, "Total coverage threshold (%d%%) satisfied:\t", thr.Total)
+	if thr.Function > 0 || result.HasFunctionOverrides { // Function threshold report
+		fmt.Fprintf(tabber, "Function coverage threshold (%v%%) satisfied:\t", thr.Function)
+		fmt.Fprint(tabber, statusStr(len(result.FunctionsBelowThreshold) == 0))
+		reportIssuesForHuman(tabber, result.FunctionsBelowThreshold)
+		fmt.Fprint(tabber, "\n")
+	}
+
+	if result.HasTotalThreshold() { // Total threshold report
+		fmt.Fprintf(tabber, "Total coverage threshold (%s) satisfied:\t",
+			thresholdStr(thr.Total, mu.Total, mul.Total))
 		fmt.Fprint(tabber, statusStr(result.MeetsTotalCoverage()))
 		fmt.Fprint(tabber, "\n")
 	}
 
+	if thr.NewCode > 0 && result.HasNewCode { // New code threshold report
+		fmt.Fprintf(tabber, "New code coverage threshold (%v%%) satisfied:\t", thr.NewCode)
+		fmt.Fprint(tabber, statusStr(result.MeetsNewCodeThreshold()))
+		fmt.Fprint(tabber, "\n")
+	}
+
+	if result.HasRatchet { // Ratchet report
+		fmt.Fprint(tabber, "Ratchet coverage satisfied:\t")
+		fmt.Fprint(tabber, statusStr(len(result.RatchetRegressions) == 0))
+		reportRatchetRegressions(tabber, result.RatchetRegressions)
+		fmt.Fprint(tabber, "\n")
+	}
+
+	reportMaxIgnored(tabber, result)
+
 	fmt.Fprintf(tabber, "Total test coverage: %s\n", result.TotalStats.Str())
 }
 
+func reportMaxIgnored(w io.Writer, result AnalyzeResult) {
+	limit := result.MaxIgnored
+
+	if limit.File > 0 {
+		fmt.Fprintf(w, "File ignored statements limit (%d%%) satisfied:\t", limit.File)
+		fmt.Fprint(w, statusStr(len(result.FilesAboveMaxIgnored) == 0))
+		reportAboveMaxIgnored(w, result.FilesAboveMaxIgnored, limit.File)
+		fmt.Fprint(w, "\n")
+	}
+
+	if limit.Package > 0 {
+		fmt.Fprintf(w, "Package ignored statements limit (%d%%) satisfied:\t", limit.Package)
+		fmt.Fprint(w, statusStr(len(result.PackagesAboveMaxIgnored) == 0))
+		reportAboveMaxIgnored(w, result.PackagesAboveMaxIgnored, limit.Package)
+		fmt.Fprint(w, "\n")
+	}
+
+	if limit.Total > 0 {
+		fmt.Fprintf(w, "Total ignored statements limit (%d%%) satisfied:\t", limit.Total)
+		fmt.Fprint(w, statusStr(result.MeetsMaxIgnoredTotal()))
+		fmt.Fprint(w, "\n")
+	}
+}
+
+func reportAboveMaxIgnored(w io.Writer, coverageStats []coverage.Stats, limit int) {
+	if len(coverageStats) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n  above limit:\tignored:\tlimit:")
+
+	coverage.SortStatsByName(coverageStats)
+
+	for _, stats := range coverageStats {
+		fmt.Fprintf(w, "\n  %s\t%s\t%d%%", stats.Name, ignoredStr(stats), limit)
+	}
+
+	fmt.Fprintf(w, "\n")
+}
+
+func reportIgnoredStatements(w io.Writer, result AnalyzeResult) {
+	if result.TotalStats.Ignored == 0 {
+		return
+	}
+
+	tabber := tabwriter.NewWriter(w, 1, 8, 2, '\t', 0) //nolint:mnd // relax
+	defer tabber.Flush()
+
+	fmt.Fprintf(tabber, "\nStatements ignored with coverage-ignore annotations:")
+	fmt.Fprintf(tabber, "\n  file:\tignored statements:\tannotations:")
+
+	files := slices.Clone(result.Files)
+	coverage.SortStatsByName(files)
+
+	for _, stats := range files {
+		if stats.Ignored == 0 {
+			continue
+		}
+
+		annotations := make([]string, len(stats.IgnoredByAnnotation))
+		for i, a := range stats.IgnoredByAnnotation {
+			annotations[i] = fmt.Sprintf("%d (%d)", a.Line, a.Ignored)
+		}
+
+		fmt.Fprintf(tabber, "\n  %s\t%s\t%s",
+			stats.Name, ignoredStr(stats), strings.Join(annotations, ", "))
+	}
+
+	fmt.Fprintf(tabber, "\n  total\t%s\t", ignoredStr(result.TotalStats))
+	fmt.Fprintf(tabber, "\n")
+}
+
+// thresholdStr returns threshold percentage, followed by limits of uncovered
+// statements and lines when they are set.
+func thresholdStr(threshold float64, maxUncovered, maxUncoveredLines int) string {
+	res := fmt.Sprintf("%v%%", threshold)
+
+	if maxUncovered > 0 {
+		res += fmt.Sprintf(", max %d uncovered", maxUncovered)
+	}
+
+	if maxUncoveredLines > 0 {
+		res += fmt.Sprintf(", max %d uncovered lines", maxUncoveredLines)
+	}
+
+	return res
+}
+
+// statsThresholdStr returns threshold string of stats.
+func statsThresholdStr(s coverage.Stats) string {
+	return thresholdStr(s.Threshold, s.MaxUncovered, s.MaxUncoveredLines)
+}
+
+// totalThresholdStr returns threshold string of total coverage.
+func totalThresholdStr(r AnalyzeResult) string {
+	return thresholdStr(r.Threshold.Total, r.MaxUncovered.Total, r.MaxUncoveredLines.Total)
+}
+
+// ignoredStr returns number of ignored statements with percentage of all statements.
+func ignoredStr(s coverage.Stats) string {
+	return fmt.Sprintf("%d (%v%%)", s.Ignored, s.IgnoredPercentageFloor())
+}
+
 func reportIssuesForHuman(w io.Writer, coverageStats []coverage.Stats) {
 	if len(coverageStats) == 0 {
 		return
 	}
 
+	hasMaxUncovered := slices.ContainsFunc(coverageStats, func(s coverage.Stats) bool {
+		return s.MaxUncovered > 0
+	})
+	hasMaxUncoveredLines := slices.ContainsFunc(coverageStats, func(s coverage.Stats) bool {
+		return s.MaxUncoveredLines > 0
+	})
+
 	fmt.Fprintf(w, "\n  below threshold:\tcoverage:\tthreshold:")
 
+	if hasMaxUncovered {
+		fmt.Fprintf(w, "\tuncovered:\tmax uncovered:")
+	}
+
+	if hasMaxUncoveredLines {
+		fmt.Fprintf(w, "\tuncovered lines:\tmax uncovered lines:")
+	}
+
 	coverage.SortStatsByName(coverageStats)
 
 	for _, stats := range coverageStats {
-		fmt.Fprintf(w, "\n  %s\t%s\t%d%%", stats.Name, stats.Str(), stats.Threshold)
+		fmt.Fprintf(w, "\n  %s\t%s\t%v%%", stats.Name, stats.Str(), stats.Threshold)
+
+		if hasMaxUncovered {
+			fmt.Fprintf(w, "\t%d\t%s", stats.UncoveredStmtCount(), maxUncoveredStr(stats.MaxUncovered))
+		}
+
+		if hasMaxUncoveredLines {
+			fmt.Fprintf(w, "\t%d\t%s",
+				stats.UncoveredLinesCount(), maxUncoveredStr(stats.MaxUncoveredLines))
+		}
+	}
+
+	fmt.Fprintf(w, "\n")
+}
+
+func maxUncoveredStr(maxUncovered int) string {
+	if maxUncovered == 0 {
+		return "-"
+	}
+
+	return strconv.Itoa(maxUncovered)
+}
+
+func reportRatchetRegressions(w io.Writer, regressions []RatchetRegression) {
+	if len(regressions) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n  regressed:\tcoverage:\tratchet:")
+
+	for _, r := range regressions {
+		fmt.Fprintf(w, "\n  %s\t%s\t%s", r.Current.Name, r.Current.Str(), r.Stored.Str())
 	}
 
 	fmt.Fprintf(w, "\n")
@@ -86,7 +271,7 @@ func reportUncoveredLines(w io.Writer, result AnalyzeResult) {
 	for _, stats := range result.FilesWithUncoveredLines {
 		if len(stats.UncoveredLines) > 0 {
 			fmt.Fprintf(tabber, "\n  %s\t", stats.Name)
-			fmt.Fprintf(tabber, "%v%%\t", stats.CoveredPercentageF())
+			fmt.Fprintf(tabber, "%v%%\t", stats.CoveredPercentageFloor())
 			compressUncoveredLines(tabber, stats.UncoveredLines)
 		}
 	}
@@ -94,6 +279,59 @@ func reportUncoveredLines(w io.Writer, result AnalyzeResult) {
 	fmt.Fprintf(tabber, "\n")
 }
 
+func reportExcludedBySource(w io.Writer, result AnalyzeResult) {
+	if len(result.FilesExcludedBySource) == 0 {
+		return
+	}
+
+	tabber := tabwriter.NewWriter(w, 1, 8, 2, '\t', 0) //nolint:mnd // relax
+	defer tabber.Flush()
+
+	fmt.Fprintf(tabber, "\nFiles excluded from coverage by source:")
+	fmt.Fprintf(tabber, "\n  file:\texcluded:\treason:")
+
+	for _, e := range sortedExclusions(result.FilesExcludedBySource) {
+		fmt.Fprintf(tabber, "\n  %s\t%s\t%s", e.Name, e.By, e.Reason)
+	}
+
+	fmt.Fprintf(tabber, "\n")
+}
+
+func sortedExclusions(exclusions []coverage.Exclusion) []coverage.Exclusion {
+	exclusions = slices.Clone(exclusions)
+	slices.SortFunc(exclusions, func(a, b coverage.Exclusion) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return exclusions
+}
+
+func reportColdBlocks(w io.Writer, result AnalyzeResult) {
+	if len(result.FilesWithColdBlocks) == 0 {
+		return
+	}
+
+	tabber := tabwriter.NewWriter(w, 1, 8, 2, '\t', 0) //nolint:mnd // relax
+	defer tabber.Flush()
+
+	fmt.Fprintf(tabber, "\nCovered blocks executed fewer than %d times:", result.ColdBlockHits)
+	fmt.Fprintf(tabber, "\n  file:\tlines:\thits:")
+
+	coverage.SortStatsByName(result.FilesWithColdBlocks)
+
+	for _, stats := range result.FilesWithColdBlocks {
+		for _, b := range stats.Blocks {
+			if b.StartLine == b.EndLine {
+				fmt.Fprintf(tabber, "\n  %s\t%d\t%d", stats.Name, b.StartLine, b.Count)
+			} else {
+				fmt.Fprintf(tabber, "\n  %s\t%d-%d\t%d", stats.Name, b.StartLine, b.EndLine, b.Count)
+			}
+		}
+	}
+
+	fmt.Fprintf(tabber, "\n")
+}
+
 func reportMissingExplanations(w io.Writer, result AnalyzeResult) {
 	if len(result.FilesWithMissingExplanations) == 0 {
 		return
@@ -119,6 +357,91 @@ func reportMissingExplanations(w io.Writer, result AnalyzeResult) {
 	fmt.Fprintf(tabber, "\n")
 }
 
+func reportUnmatchedAnnotations(w io.Writer, result AnalyzeResult) {
+	if len(result.FilesWithUnmatchedAnnotations) == 0 {
+		return
+	}
+
+	tabber := tabwriter.NewWriter(w, 1, 8, 2, '\t', 0) //nolint:mnd // relax
+	defer tabber.Flush()
+
+	fmt.Fprintf(tabber, "\nFiles with unmatched coverage-ignore-start/end annotation"+
+		" or misplaced directive:")
+	fmt.Fprintf(tabber, "\n  file:\tline numbers:")
+
+	coverage.SortStatsByName(result.FilesWithUnmatchedAnnotations)
+
+	for _, stats := range result.FilesWithUnmatchedAnnotations {
+		lines := sliceIntsStr(stats.UnmatchedAnnotations, ", ")
+		fmt.Fprintf(tabber, "\n  %s\t%s", stats.Name, lines)
+	}
+
+	fmt.Fprintf(tabber, "\n")
+}
+
+func reportExpiredAnnotations(w io.Writer, result AnalyzeResult) {
+	if len(result.FilesWithExpiredAnnotations) == 0 {
+		return
+	}
+
+	tabber := tabwriter.NewWriter(w, 1, 8, 2, '\t', 0) //nolint:mnd // relax
+	defer tabber.Flush()
+
+	fmt.Fprintf(tabber, "\nFiles with expired coverage-ignore annotation:")
+	fmt.Fprintf(tabber, "\n  file:\tline numbers:")
+
+	coverage.SortStatsByName(result.FilesWithExpiredAnnotations)
+
+	for _, stats := range result.FilesWithExpiredAnnotations {
+		lines := sliceIntsStr(stats.ExpiredAnnotations, ", ")
+		fmt.Fprintf(tabber, "\n  %s\t%s", stats.Name, lines)
+	}
+
+	fmt.Fprintf(tabber, "\n")
+}
+
+func reportInvalidAnnotations(w io.Writer, result AnalyzeResult) {
+	if len(result.FilesWithInvalidAnnotations) == 0 {
+		return
+	}
+
+	tabber := tabwriter.NewWriter(w, 1, 8, 2, '\t', 0) //nolint:mnd // relax
+	defer tabber.Flush()
+
+	fmt.Fprintf(tabber, "\nFiles with invalid until date of coverage-ignore annotation:")
+	fmt.Fprintf(tabber, "\n  file:\tline numbers:")
+
+	coverage.SortStatsByName(result.FilesWithInvalidAnnotations)
+
+	for _, stats := range result.FilesWithInvalidAnnotations {
+		lines := sliceIntsStr(stats.InvalidAnnotations, ", ")
+		fmt.Fprintf(tabber, "\n  %s\t%s", stats.Name, lines)
+	}
+
+	fmt.Fprintf(tabber, "\n")
+}
+
+func reportMissingIssues(w io.Writer, result AnalyzeResult) {
+	if len(result.FilesWithMissingIssues) == 0 {
+		return
+	}
+
+	tabber := tabwriter.NewWriter(w, 1, 8, 2, '\t', 0) //nolint:mnd // relax
+	defer tabber.Flush()
+
+	fmt.Fprintf(tabber, "\nFiles with missing issue reference for coverage-ignore annotation:")
+	fmt.Fprintf(tabber, "\n  file:\tline numbers:")
+
+	coverage.SortStatsByName(result.FilesWithMissingIssues)
+
+	for _, stats := range result.FilesWithMissingIssues {
+		lines := sliceIntsStr(stats.AnnotationsWithoutIssue, ", ")
+		fmt.Fprintf(tabber, "\n  %s\t%s", stats.Name, lines)
+	}
+
+	fmt.Fprintf(tabber, "\n")
+}
+
 //nolint:lll // relax
 func reportDiff(w io.Writer, result AnalyzeResult) {
 	if !result.HasBaseBreakdown {
@@ -156,56 +479,95 @@ func reportDiff(w io.Writer, result AnalyzeResult) {
 	}
 
 	fmt.Fprintf(tabber, "\n")
-}
 
-func ReportForGithubAction(w io.Writer, result AnalyzeResult) {
-	out := bufio.NewWriter(w)
-	defer out.Flush()
+	reportLinesDiff(tabber, result.Diff,
+		"Lines that became uncovered compared to the base:",
+		func(d FileCoverageDiff) []int { return d.NewlyUncoveredLines },
+	)
+	reportLinesDiff(tabber, result.Diff,
+		"Lines that became covered compared to the base:",
+		func(d FileCoverageDiff) []int { return d.NewlyCoveredLines },
+	)
+}
 
-	reportLineError := func(file, title, msg string, line int) {
-		fmt.Fprintf(out, "::error file=%s,title=%s,line=%d::%s\n", file, title, line, msg)
+func reportLinesDiff(
+	w io.Writer,
+	diff []FileCoverageDiff,
+	title string,
+	lines func(FileCoverageDiff) []int,
+) {
+	hasLines := slices.ContainsFunc(diff, func(d FileCoverageDiff) bool {
+		return len(lines(d)) > 0
+	})
+	if !hasLines {
+		return
 	}
-	reportError := func(title, msg string) {
-		fmt.Fprintf(out, "::error title=%s::%s\n", title, msg)
+
+	fmt.Fprintf(w, "\n%s", title)
+	fmt.Fprintf(w, "\n  file:\tlines:")
+
+	for _, d := range diff {
+		if l := lines(d); len(l) > 0 {
+			fmt.Fprintf(w, "\n  %s\t", d.Current.Name)
+			compressUncoveredLines(w, l)
+		}
 	}
 
-	coverage.SortStatsByName(result.FilesBelowThreshold)
-	coverage.SortStatsByName(result.PackagesBelowThreshold)
-	coverage.SortStatsByName(result.FilesWithMissingExplanations)
+	fmt.Fprintf(w, "\n")
+}
 
-	for _, stats := range result.FilesBelowThreshold {
-		title := "File test coverage below threshold"
-		msg := fmt.Sprintf(
-			"%s: coverage: %s; threshold: %d%%",
-			title, stats.Str(), stats.Threshold,
-		)
-		reportLineError(stats.Name, title, msg, 1)
+func reportNewCode(w io.Writer, result AnalyzeResult) {
+	if !result.HasNewCode {
+		return
 	}
 
-	for _, stats := range result.PackagesBelowThreshold {
-		title := "Package test coverage below threshold"
-		msg := fmt.Sprintf(
-			"%s: package: %s; coverage: %s; threshold: %d%%",
-			title, stats.Name, stats.Str(), stats.Threshold,
-		)
-		reportError(title, msg)
+	tabber := tabwriter.NewWriter(w, 1, 8, 2, '\t', 0) //nolint:mnd // relax
+	defer tabber.Flush()
+
+	total := coverage.StatsCalcTotal(result.NewCode)
+	fmt.Fprintf(tabber, "\nNew code test coverage: %s\n", total.Str())
+
+	withUncovered := coverage.StatsFilterWithUncoveredLines(result.NewCode)
+	if len(withUncovered) == 0 {
+		return
 	}
 
-	if !result.MeetsTotalCoverage() {
-		title := "Total test coverage below threshold"
-		msg := fmt.Sprintf(
-			"%s: coverage: %s; threshold: %d%%",
-			title, result.TotalStats.Str(), result.Threshold.Total,
-		)
-		reportError(title, msg)
+	fmt.Fprintf(tabber, "\nChanged lines without coverage:")
+	fmt.Fprintf(tabber, "\n  file:\tcoverage:\tuncovered lines:")
+
+	coverage.SortStatsByName(withUncovered)
+
+	for _, stats := range withUncovered {
+		fmt.Fprintf(tabber, "\n  %s\t%s\t", stats.Name, stats.Str())
+		compressUncoveredLines(tabber, stats.UncoveredLines)
 	}
 
-	for _, stats := range result.FilesWithMissingExplanations {
-		for _, line := range stats.AnnotationsWithoutComments {
-			title := "Missing explanation for coverage-ignore"
-			msg := title + ": add an explanation after the coverage-ignore annotation"
+	fmt.Fprintf(tabber, "\n")
+}
+
+func ReportForGithubAction(w io.Writer, result AnalyzeResult) {
+	out := bufio.NewWriter(w)
+	defer out.Flush()
+
+	reportLineError := func(file, title, msg string, line int) {
+		fmt.Fprintf(out, "::error file=%s,title=%s,line=%d::%s\n", file, title, line, msg)
+	}
+	reportRangeError := func(file, title, msg string, start, end int) {
+		fmt.Fprintf(out, "::error file=%s,title=%s,line=%d,endLine=%d::%s\n",
+			file, title, start, end, msg)
+	}
+	reportError := func(title, msg string) {
+		fmt.Fprintf(out, "::error title=%s::%s\n", title, msg)
+	}
 
-			reportLineError(stats.Name, title, msg, line)
+	for _, i := range reportIssues(result) {
+		switch {
+		case i.global:
+			reportError(i.title, i.msg)
+		case i.end > 0:
+			reportRangeError(i.file, i.title, i.msg, i.start, i.end)
+		default:
+			reportLineError(i.file, i.title, i.msg, i.start)
 		}
 	}
 }
@@ -224,17 +586,24 @@ func SetGithubActionOutput(result AnalyzeResult, report string) error {
 		return fmt.Errorf("could not open GitHub output file: %w", err)
 	}
 
-	totalStr := strconv.Itoa(result.TotalStats.CoveredPercentage())
+	total := badgeCoverage(result)
+	totalStr := strconv.Itoa(total)
 
 	return errors.Join(
 		setOutputValue(file, gaOutputTotalCoverage, totalStr),
-		setOutputValue(file, gaOutputBadgeColor, badge.Color(result.TotalStats.CoveredPercentage())),
+		setOutputValue(file, gaOutputBadgeColor, badge.Color(total)),
 		setOutputValue(file, gaOutputBadgeText, totalStr+"%"),
 		setOutputValue(file, gaOutputReport, marshalReportValue(report)),
 		file.Close(),
 	)
 }
 
+// badgeCoverage returns total coverage used for badge and outputs. It is rounded
+// down, same as displayed coverage, so it is never above actual coverage.
+func badgeCoverage(result AnalyzeResult) int {
+	return int(result.TotalStats.CoveredPercentageFloor())
+}
+
 func openGitHubOutput(p string) (io.WriteCloser, error) {
 	//nolint:mnd,wrapcheck,gosec // error is wrapped at level above
 	return os.OpenFile(p, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
@@ -258,29 +627,37 @@ func marshalReportValue(s string) string {
 
 func compressUncoveredLines(w io.Writer, ull []int) {
 	separator := ""
-	printRange := func(a, b int) {
-		if a == b {
-			fmt.Fprintf(w, "%v%v", separator, a)
+
+	for _, r := range lineRanges(ull) {
+		if r[0] == r[1] {
+			fmt.Fprintf(w, "%v%v", separator, r[0])
 		} else {
-			fmt.Fprintf(w, "%v%v-%v", separator, a, b)
+			fmt.Fprintf(w, "%v%v-%v", separator, r[0], r[1])
 		}
 
 		separator = " "
 	}
+}
+
+// lineRanges groups sorted line numbers into ranges of consecutive lines.
+func lineRanges(ull []int) [][2]int {
+	var ranges [][2]int
 
 	last := -1
 	for i := range ull {
 		if last == -1 {
 			last = ull[i]
 		} else if ull[i-1]+1 != ull[i] {
-			printRange(last, ull[i-1])
+			ranges = append(ranges, [2]int{last, ull[i-1]})
 			last = ull[i]
 		}
 	}
 
 	if last != -1 {
-		printRange(last, ull[len(ull)-1])
+		ranges = append(ranges, [2]int{last, ull[len(ull)-1]})
 	}
+
+	return ranges
 }
 
 func statusStr(passing bool) string {
@@ -299,3 +676,12 @@ func sliceIntsStr(s []int, sep string) string {
 
 	return strings.Join(strs, sep)
 }
+
+// sortedStats returns copy of stats sorted by name, leaving analysis result
+// which may be used by other reports unchanged.
+func sortedStats(stats []coverage.Stats) []coverage.Stats {
+	stats = slices.Clone(stats)
+	coverage.SortStatsByName(stats)
+
+	return stats
+}
@@ -0,0 +1,92 @@
+package testcoverage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/checkrun"
+	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/coverage"
+)
+
+// ReportForGithubCheckRun creates GitHub check run with markdown report as summary
+// and annotations of issues found by analysis. Unlike workflow commands made by
+// ReportForGithubAction, number of annotations is not limited and files below
+// threshold are annotated on their uncovered lines.
+func ReportForGithubCheckRun(w io.Writer, result AnalyzeResult, cfg checkrun.Github) error {
+	summary := &bytes.Buffer{}
+	ReportForMarkdown(summary, result, githubBlobURL())
+
+	url, err := checkrun.Create(cfg, checkrun.Run{
+		Title: fmt.Sprintf("%s: total test coverage %s",
+			statusStr(result.Pass()), result.TotalStats.Str()),
+		Summary:     summary.String(),
+		Success:     result.Pass(),
+		Annotations: makeCheckRunAnnotations(result),
+	})
+	if err != nil {
+		return fmt.Errorf("create github check run: %w", err)
+	}
+
+	fmt.Fprintf(w, "\nGitHub check run created: %s\n", url)
+
+	return nil
+}
+
+func makeCheckRunAnnotations(result AnalyzeResult) []checkrun.Annotation {
+	var res []checkrun.Annotation
+
+	add := func(path, title, msg string, start, end int) {
+		res = append(res, checkrun.Annotation{
+			Path:      path,
+			StartLine: start,
+			EndLine:   end,
+			Level:     checkrun.LevelFailure,
+			Title:     title,
+			Message:   msg,
+		})
+	}
+
+	for _, stats := range sortedStats(result.FilesBelowThreshold) {
+		title := "File test coverage below threshold"
+		msg := fmt.Sprintf(
+			"%s: coverage: %s; threshold: %s",
+			title, stats.Str(), statsThresholdStr(stats),
+		)
+
+		ranges := lineRanges(stats.UncoveredLines)
+		if len(ranges) == 0 { // uncovered lines are not known, so whole file is annotated
+			ranges = [][2]int{{1, 1}}
+		}
+
+		for _, r := range ranges {
+			add(stats.Name, title, msg, r[0], r[1])
+		}
+	}
+
+	for _, ai := range annotationIssues(result) {
+		for _, stats := range sortedStats(ai.files) {
+			for _, line := range ai.lines(stats) {
+				add(stats.Name, ai.title, ai.title+": "+ai.hint, line, line)
+			}
+		}
+	}
+
+	if !result.MeetsNewCodeThreshold() {
+		newCode := sortedStats(coverage.StatsFilterWithUncoveredLines(result.NewCode))
+
+		title := "Changed lines not covered by tests"
+		msg := fmt.Sprintf(
+			"%s: new code coverage: %s; threshold: %v%%",
+			title, coverage.StatsCalcTotal(result.NewCode).Str(), result.Threshold.NewCode,
+		)
+
+		for _, stats := range newCode {
+			for _, r := range lineRanges(stats.UncoveredLines) {
+				add(stats.Name, title, msg, r[0], r[1])
+			}
+		}
+	}
+
+	return res
+}
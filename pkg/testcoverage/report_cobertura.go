@@ -0,0 +1,195 @@
+package testcoverage
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/coverage"
+)
+
+const coberturaDocType = `<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">`
+
+type coberturaCoverage struct {
+	XMLName         xml.Name           `xml:"coverage"`
+	LineRate        string             `xml:"line-rate,attr"`
+	BranchRate      string             `xml:"branch-rate,attr"`
+	LinesCovered    int                `xml:"lines-covered,attr"`
+	LinesValid      int                `xml:"lines-valid,attr"`
+	BranchesCovered int                `xml:"branches-covered,attr"`
+	BranchesValid   int                `xml:"branches-valid,attr"`
+	Complexity      int                `xml:"complexity,attr"`
+	Version         string             `xml:"version,attr"`
+	Timestamp       int64              `xml:"timestamp,attr"`
+	Sources         []string           `xml:"sources>source"`
+	Packages        []coberturaPackage `xml:"packages>package"`
+}
+
+type coberturaPackage struct {
+	Name       string           `xml:"name,attr"`
+	LineRate   string           `xml:"line-rate,attr"`
+	BranchRate string           `xml:"branch-rate,attr"`
+	Complexity int              `xml:"complexity,attr"`
+	Classes    []coberturaClass `xml:"classes>class"`
+}
+
+type coberturaClass struct {
+	Name       string            `xml:"name,attr"`
+	Filename   string            `xml:"filename,attr"`
+	LineRate   string            `xml:"line-rate,attr"`
+	BranchRate string            `xml:"branch-rate,attr"`
+	Complexity int               `xml:"complexity,attr"`
+	Methods    []coberturaMethod `xml:"methods>method"`
+	Lines      []coberturaLine   `xml:"lines>line"`
+}
+
+type coberturaMethod struct {
+	Name       string          `xml:"name,attr"`
+	Signature  string          `xml:"signature,attr"`
+	LineRate   string          `xml:"line-rate,attr"`
+	BranchRate string          `xml:"branch-rate,attr"`
+	Complexity int             `xml:"complexity,attr"`
+	Lines      []coberturaLine `xml:"lines>line"`
+}
+
+type coberturaLine struct {
+	Number int  `xml:"number,attr"`
+	Hits   int  `xml:"hits,attr"`
+	Branch bool `xml:"branch,attr"`
+}
+
+// ReportForCobertura writes coverage report in Cobertura XML format. Report is made
+// from analyzed stats, so excluded files and code ignored with annotations are not
+// part of it. Since Go coverage profiles do not hold branch data, branch rates are 0.
+func ReportForCobertura(w io.Writer, result AnalyzeResult) error {
+	c := makeCoberturaCoverage(result)
+
+	if _, err := io.WriteString(w, xml.Header+coberturaDocType+"\n"); err != nil {
+		return fmt.Errorf("writing cobertura header: %w", err)
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(c); err != nil {
+		return fmt.Errorf("encoding cobertura report: %w", err)
+	}
+
+	return nil
+}
+
+func makeCoberturaCoverage(result AnalyzeResult) coberturaCoverage {
+	c := coberturaCoverage{
+		BranchRate: lineRate(0, 0),
+		Version:    toolVersion(),
+		Timestamp:  time.Now().UnixMilli(),
+		Sources:    sourceRoots(result.Files),
+	}
+
+	files := slices.Clone(result.Files)
+	coverage.SortStatsByName(files)
+
+	for _, p := range result.Packages {
+		pkg := coberturaPackage{Name: p.Name}
+		pkgCovered, pkgValid := 0, 0
+
+		for _, f := range files {
+			if packageForFile(f.Name) != p.Name {
+				continue
+			}
+
+			class, covered, valid := makeCoberturaClass(f)
+			pkg.Classes = append(pkg.Classes, class)
+			pkgCovered += covered
+			pkgValid += valid
+		}
+
+		pkg.LineRate = lineRate(pkgCovered, pkgValid)
+		pkg.BranchRate = lineRate(0, 0)
+		c.Packages = append(c.Packages, pkg)
+		c.LinesCovered += pkgCovered
+		c.LinesValid += pkgValid
+	}
+
+	c.LineRate = lineRate(c.LinesCovered, c.LinesValid)
+
+	return c
+}
+
+func makeCoberturaClass(s coverage.Stats) (coberturaClass, int, int) {
+	hits := s.LineHits()
+	lines, covered := coberturaLines(hits, 0, math.MaxInt)
+
+	class := coberturaClass{
+		Name:       strings.TrimSuffix(filepath.Base(s.Name), ".go"),
+		Filename:   s.Name,
+		LineRate:   lineRate(covered, len(lines)),
+		BranchRate: lineRate(0, 0),
+		Lines:      lines,
+	}
+
+	for _, fn := range s.Functions {
+		methodLines, methodCovered := coberturaLines(hits, fn.StartLine, fn.EndLine)
+		class.Methods = append(class.Methods, coberturaMethod{
+			Name:       fn.Name,
+			LineRate:   lineRate(methodCovered, len(methodLines)),
+			BranchRate: lineRate(0, 0),
+			Lines:      methodLines,
+		})
+	}
+
+	return class, covered, len(lines)
+}
+
+func coberturaLines(hits []coverage.LineHit, start, end int) ([]coberturaLine, int) {
+	var (
+		lines   []coberturaLine
+		covered int
+	)
+
+	for _, h := range hits {
+		if h.Line < start || h.Line > end {
+			continue
+		}
+
+		lines = append(lines, coberturaLine{Number: h.Line, Hits: h.Hits})
+		if h.Hits > 0 {
+			covered++
+		}
+	}
+
+	return lines, covered
+}
+
+func lineRate(covered, valid int) string {
+	if valid == 0 {
+		return "1" // nothing to cover is reported as fully covered
+	}
+
+	return fmt.Sprintf("%.4f", float64(covered)/float64(valid))
+}
+
+// sourceRoots returns absolute paths of directories, relative to which
+// file names are resolved.
+func sourceRoots(files []coverage.Stats) []string {
+	var roots []string
+
+	for _, f := range files {
+		root, ok := strings.CutSuffix(filepath.ToSlash(f.Path), f.Name)
+		if !ok {
+			continue
+		}
+
+		root, err := filepath.Abs(filepath.FromSlash(root))
+		if err == nil && !slices.Contains(roots, root) {
+			roots = append(roots, root)
+		}
+	}
+
+	return roots
+}
@@ -0,0 +1,120 @@
+package testcoverage
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/badge"
+)
+
+const (
+	glOutputDirEnv        = "CI_PROJECT_DIR"
+	glCodeQualityFile     = "gl-code-quality-report.json"
+	glDotenvFile          = "gl-coverage.env"
+	glOutputTotalCoverage = "TOTAL_COVERAGE"
+	glOutputBadgeColor    = "BADGE_COLOR"
+	glOutputBadgeText     = "BADGE_TEXT"
+)
+
+type glCodeQualityIssue struct {
+	Description string                `json:"description"`
+	CheckName   string                `json:"check_name"`
+	Fingerprint string                `json:"fingerprint"`
+	Severity    string                `json:"severity"`
+	Location    glCodeQualityLocation `json:"location"`
+}
+
+type glCodeQualityLocation struct {
+	Path  string             `json:"path"`
+	Lines glCodeQualityLines `json:"lines"`
+}
+
+type glCodeQualityLines struct {
+	Begin int `json:"begin"`
+	End   int `json:"end,omitempty"`
+}
+
+// ReportForGitlab writes total coverage in format which can be matched with
+// GitLab's job `coverage` regex, eg. `/Coverage: \d+\.\d+%/`.
+func ReportForGitlab(w io.Writer, result AnalyzeResult) {
+	out := bufio.NewWriter(w)
+	defer out.Flush()
+
+	fmt.Fprintf(out, "Coverage: %.1f%%\n", result.TotalStats.CoveredPercentageFloor())
+}
+
+// ReportForGitlabCodeQuality writes issues found by analysis as GitLab Code Quality
+// report. Fingerprints of issues do not depend on coverage values, so the same issue
+// is recognized between pipelines.
+func ReportForGitlabCodeQuality(w io.Writer, result AnalyzeResult) error {
+	issues := []glCodeQualityIssue{}
+
+	add := func(check, desc, path string, begin, end int) {
+		sum := sha256.Sum256(fmt.Appendf(nil, "%s:%s:%d:%d", check, path, begin, end))
+		issues = append(issues, glCodeQualityIssue{
+			Description: desc,
+			CheckName:   check,
+			Fingerprint: hex.EncodeToString(sum[:]),
+			Severity:    "major",
+			Location: glCodeQualityLocation{
+				Path:  path,
+				Lines: glCodeQualityLines{Begin: begin, End: end},
+			},
+		})
+	}
+
+	for _, i := range reportIssues(result) {
+		// issues of packages and total coverage are not related to lines of file, but
+		// code quality report requires begin line, so they are attached to first line.
+		add(i.id, i.msg, i.file, max(i.start, 1), i.end)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(issues); err != nil {
+		return fmt.Errorf("encoding code quality report: %w", err)
+	}
+
+	return nil
+}
+
+// SetGitlabOutput writes Code Quality report and dotenv file with total coverage
+// and badge values. Files are written to project directory of GitLab job.
+func SetGitlabOutput(result AnalyzeResult) error {
+	dir := os.Getenv(glOutputDirEnv)
+
+	buf := &bytes.Buffer{}
+	if err := ReportForGitlabCodeQuality(buf, result); err != nil { // coverage-ignore
+		return err
+	}
+
+	//nolint:mnd,gosec // relax
+	if err := os.WriteFile(filepath.Join(dir, glCodeQualityFile), buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("could not write code quality report: %w", err)
+	}
+
+	total := badgeCoverage(result)
+	totalStr := strconv.Itoa(total)
+	badgeColor := badge.Color(total)
+
+	dotenv := &bytes.Buffer{}
+	fmt.Fprintf(dotenv, "%s=%s\n", glOutputTotalCoverage, totalStr)
+	fmt.Fprintf(dotenv, "%s=%s\n", glOutputBadgeColor, badgeColor)
+	fmt.Fprintf(dotenv, "%s=%s\n", glOutputBadgeText, totalStr+"%")
+
+	//nolint:mnd,gosec // relax
+	if err := os.WriteFile(filepath.Join(dir, glDotenvFile), dotenv.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("could not write dotenv file: %w", err)
+	}
+
+	return nil
+}
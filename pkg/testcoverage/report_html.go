@@ -0,0 +1,197 @@
+package testcoverage
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/coverage"
+)
+
+//go:embed report_html.tmpl
+var htmlReportTemplate string
+
+// ReportForHTML writes self-contained HTML report, which holds package tree with
+// coverage stats, annotated source of each file and coverage difference compared
+// to the base breakdown (when base breakdown is loaded).
+func ReportForHTML(w io.Writer, result AnalyzeResult) error {
+	tmpl, err := template.New("report").Funcs(template.FuncMap{
+		"statusStr": statusStr,
+		"lines":     formatLines,
+	}).Parse(htmlReportTemplate)
+	if err != nil { // coverage-ignore
+		return fmt.Errorf("parsing html template: %w", err)
+	}
+
+	if err := tmpl.Execute(w, makeHTMLReport(result)); err != nil {
+		return fmt.Errorf("executing html template: %w", err)
+	}
+
+	return nil
+}
+
+type htmlReport struct {
+	Total          coverage.Stats
+	Thresholds     []thresholdCheck
+	Packages       []htmlPackage
+	HasBase        bool
+	DiffPercentage float64
+	Diff           []FileCoverageDiff
+}
+
+type thresholdCheck struct {
+	Name  string
+	Value string
+	Pass  bool
+}
+
+type htmlPackage struct {
+	htmlStats
+	Files []htmlFile
+}
+
+type htmlStats struct {
+	coverage.Stats
+	HasThreshold bool
+	Pass         bool
+}
+
+type htmlFile struct {
+	htmlStats
+	ID        string
+	Lines     []htmlLine
+	SourceErr string
+}
+
+type htmlLine struct {
+	Number int
+	Text   string
+	Class  string
+}
+
+func makeHTMLReport(result AnalyzeResult) htmlReport {
+	r := htmlReport{
+		Total:          result.TotalStats,
+		Thresholds:     makeThresholdChecks(result),
+		HasBase:        result.HasBaseBreakdown,
+		DiffPercentage: result.DiffPercentage,
+		Diff:           result.Diff,
+	}
+
+	files := slices.Clone(result.Files)
+	coverage.SortStatsByName(files)
+
+	for _, p := range result.Packages {
+		pkg := htmlPackage{htmlStats: makeHTMLStats(p)}
+
+		for i, f := range files {
+			if packageForFile(f.Name) == p.Name {
+				pkg.Files = append(pkg.Files, makeHTMLFile(f, i))
+			}
+		}
+
+		r.Packages = append(r.Packages, pkg)
+	}
+
+	return r
+}
+
+func makeThresholdChecks(result AnalyzeResult) []thresholdCheck {
+	var res []thresholdCheck
+
+	thr := result.Threshold
+	mu := result.MaxUncovered
+	mul := result.MaxUncoveredLines
+
+	add := func(name string, value string, enabled, pass bool) {
+		if enabled {
+			res = append(res, thresholdCheck{
+				Name:  name + " coverage threshold",
+				Value: value,
+				Pass:  pass,
+			})
+		}
+	}
+
+	add("File", thresholdStr(thr.File, mu.File, mul.File), result.HasFileThreshold(),
+		len(result.FilesBelowThreshold) == 0)
+	add("Package", thresholdStr(thr.Package, mu.Package, mul.Package),
+		result.HasPackageThreshold(), len(result.PackagesBelowThreshold) == 0)
+	add("Function", thresholdStr(thr.Function, 0, 0),
+		thr.Function > 0 || result.HasFunctionOverrides, len(result.FunctionsBelowThreshold) == 0)
+	add("Total", thresholdStr(thr.Total, mu.Total, mul.Total), result.HasTotalThreshold(),
+		result.MeetsTotalCoverage())
+	add("New code", thresholdStr(thr.NewCode, 0, 0), thr.NewCode > 0 && result.HasNewCode,
+		result.MeetsNewCodeThreshold())
+
+	if result.DiffThreshold != nil && result.HasBaseBreakdown {
+		res = append(res, thresholdCheck{
+			Name:  "Coverage difference threshold",
+			Value: fmt.Sprintf("%.2f%%", *result.DiffThreshold),
+			Pass:  result.MeetsDiffThreshold(),
+		})
+	}
+
+	return res
+}
+
+// ThresholdStr returns threshold and limits of uncovered statements and lines of stats.
+func (s htmlStats) ThresholdStr() string {
+	return statsThresholdStr(s.Stats)
+}
+
+func makeHTMLStats(s coverage.Stats) htmlStats {
+	return htmlStats{
+		Stats:        s,
+		HasThreshold: s.Threshold > 0 || s.MaxUncovered > 0 || s.MaxUncoveredLines > 0,
+		Pass:         meetsThreshold(s),
+	}
+}
+
+func makeHTMLFile(s coverage.Stats, idx int) htmlFile {
+	f := htmlFile{
+		htmlStats: makeHTMLStats(s),
+		ID:        fmt.Sprintf("file-%d", idx),
+	}
+
+	source, err := os.ReadFile(s.Path)
+	if err != nil {
+		f.SourceErr = "source file is not available"
+		return f
+	}
+
+	for i, text := range strings.Split(strings.TrimSuffix(string(source), "\n"), "\n") {
+		n := i + 1
+		f.Lines = append(f.Lines, htmlLine{
+			Number: n,
+			Text:   strings.ReplaceAll(text, "\t", "    "),
+			Class:  lineClass(s, n),
+		})
+	}
+
+	return f
+}
+
+func lineClass(s coverage.Stats, line int) string {
+	switch {
+	case slices.Contains(s.UncoveredLines, line):
+		return "uncovered"
+	case slices.Contains(s.CoveredLines, line):
+		return "covered"
+	case slices.Contains(s.IgnoredLines, line):
+		return "ignored"
+	default:
+		return ""
+	}
+}
+
+func formatLines(lines []int) string {
+	sb := &strings.Builder{}
+	compressUncoveredLines(sb, lines)
+
+	return sb.String()
+}
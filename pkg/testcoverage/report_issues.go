@@ -0,0 +1,208 @@
+package testcoverage
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/coverage"
+)
+
+// modFile is file which issues of total coverage are attached to.
+const modFile = "go.mod"
+
+// reportIssue is issue found by analysis, which is annotated in file by
+// reports of GitHub action, SARIF and GitLab code quality.
+type reportIssue struct {
+	id    string // rule of sarif and check of gitlab code quality reports
+	title string
+	msg   string
+	file  string
+	start int // first line of issue; 0 when issue is not related to lines of file
+	end   int // last line of issue; 0 when issue is on single line
+
+	// global is set for issues of packages and total coverage, which are not
+	// related to single file. These are attached to first file of package or
+	// go.mod only in reports which require location of issue.
+	global bool
+}
+
+// reportIssues returns issues found by analysis, ordered same as in human report.
+func reportIssues(result AnalyzeResult) []reportIssue {
+	res := thresholdIssues(result)
+
+	for _, ai := range annotationIssues(result) {
+		for _, stats := range sortedStats(ai.files) {
+			for _, line := range ai.lines(stats) {
+				res = append(res, reportIssue{
+					id:    ai.id,
+					title: ai.title,
+					msg:   ai.title + ": " + ai.hint,
+					file:  stats.Name,
+					start: line,
+				})
+			}
+		}
+	}
+
+	return append(res, newCodeIssues(result)...)
+}
+
+func thresholdIssues(result AnalyzeResult) []reportIssue {
+	var res []reportIssue
+
+	for _, stats := range sortedStats(result.FilesBelowThreshold) {
+		title := "File test coverage below threshold"
+		res = append(res, reportIssue{
+			id:    sarifRuleFileThreshold,
+			title: title,
+			msg: fmt.Sprintf("%s: coverage: %s; threshold: %s",
+				title, stats.Str(), statsThresholdStr(stats)),
+			file:  stats.Name,
+			start: 1,
+		})
+	}
+
+	for _, stats := range sortedStats(result.PackagesBelowThreshold) {
+		title := "Package test coverage below threshold"
+		res = append(res, reportIssue{
+			id:    sarifRulePackageThreshold,
+			title: title,
+			msg: fmt.Sprintf("%s: package: %s; coverage: %s; threshold: %s",
+				title, stats.Name, stats.Str(), statsThresholdStr(stats)),
+			file:   packageFile(result.Files, stats.Name),
+			global: true,
+		})
+	}
+
+	for _, stats := range sortedStats(result.FunctionsBelowThreshold) {
+		title := "Function test coverage below threshold"
+		file, fn, line := funcPosition(stats)
+		res = append(res, reportIssue{
+			id:    sarifRuleFunctionThreshold,
+			title: title,
+			msg: fmt.Sprintf("%s: function: %s; coverage: %s; threshold: %s",
+				title, fn, stats.Str(), statsThresholdStr(stats)),
+			file:  file,
+			start: line,
+		})
+	}
+
+	if !result.MeetsTotalCoverage() {
+		title := "Total test coverage below threshold"
+		res = append(res, reportIssue{
+			id:    sarifRuleTotalThreshold,
+			title: title,
+			msg: fmt.Sprintf("%s: coverage: %s; threshold: %s",
+				title, result.TotalStats.Str(), totalThresholdStr(result)),
+			file:   modFile,
+			global: true,
+		})
+	}
+
+	return res
+}
+
+func newCodeIssues(result AnalyzeResult) []reportIssue {
+	if result.MeetsNewCodeThreshold() {
+		return nil
+	}
+
+	var res []reportIssue
+
+	title := "Changed lines not covered by tests"
+	msg := fmt.Sprintf("%s: new code coverage: %s; threshold: %v%%",
+		title, coverage.StatsCalcTotal(result.NewCode).Str(), result.Threshold.NewCode)
+
+	for _, stats := range sortedStats(coverage.StatsFilterWithUncoveredLines(result.NewCode)) {
+		for _, r := range lineRanges(stats.UncoveredLines) {
+			res = append(res, reportIssue{
+				id:    sarifRuleNewCodeThreshold,
+				title: title,
+				msg:   msg,
+				file:  stats.Name,
+				start: r[0],
+				end:   r[1],
+			})
+		}
+	}
+
+	return res
+}
+
+// annotationIssue describes issue of coverage-ignore annotations, which is
+// reported on each line of annotations found in files.
+type annotationIssue struct {
+	id    string
+	title string
+	hint  string
+	files []coverage.Stats
+	lines func(s coverage.Stats) []int
+}
+
+func annotationIssues(result AnalyzeResult) []annotationIssue {
+	return []annotationIssue{
+		{
+			id:    sarifRuleMissingExplanation,
+			title: "Missing explanation for coverage-ignore",
+			hint:  "add an explanation after the coverage-ignore annotation",
+			files: result.FilesWithMissingExplanations,
+			lines: func(s coverage.Stats) []int { return s.AnnotationsWithoutComments },
+		},
+		{
+			id:    sarifRuleUnmatchedAnnotation,
+			title: "Unmatched coverage-ignore range annotation",
+			hint: "coverage-ignore-start and coverage-ignore-end annotations should be " +
+				"paired, and file or package directives should be in file header",
+			files: result.FilesWithUnmatchedAnnotations,
+			lines: func(s coverage.Stats) []int { return s.UnmatchedAnnotations },
+		},
+		{
+			id:    sarifRuleExpiredAnnotation,
+			title: "Expired coverage-ignore annotation",
+			hint:  "cover the code with tests or extend the until date of the annotation",
+			files: result.FilesWithExpiredAnnotations,
+			lines: func(s coverage.Stats) []int { return s.ExpiredAnnotations },
+		},
+		{
+			id:    sarifRuleInvalidAnnotation,
+			title: "Invalid coverage-ignore annotation",
+			hint:  "until date of the annotation should be in YYYY-MM-DD format",
+			files: result.FilesWithInvalidAnnotations,
+			lines: func(s coverage.Stats) []int { return s.InvalidAnnotations },
+		},
+		{
+			id:    sarifRuleMissingIssue,
+			title: "Missing issue reference for coverage-ignore",
+			hint:  "add issue=<reference> after the coverage-ignore annotation",
+			files: result.FilesWithMissingIssues,
+			lines: func(s coverage.Stats) []int { return s.AnnotationsWithoutIssue },
+		},
+	}
+}
+
+// packageFile returns first file of package, or go.mod when package has no files.
+func packageFile(files []coverage.Stats, pkg string) string {
+	names := coverage.StatsPluckName(files)
+	slices.Sort(names)
+
+	for _, name := range names {
+		if packageForFile(name) == pkg {
+			return name
+		}
+	}
+
+	return modFile
+}
+
+// funcPosition returns file, name and start line of function from its stats,
+// which are named in format `file.go:Func`. When position of function is not
+// known, first line of file is returned.
+func funcPosition(s coverage.Stats) (string, string, int) {
+	file, fn, _ := strings.Cut(s.Name, ":")
+	if len(s.Functions) == 0 {
+		return file, fn, 1
+	}
+
+	return file, fn, s.Functions[0].StartLine
+}
@@ -0,0 +1,336 @@
+package testcoverage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/coverage"
+)
+
+// JSONReportVersion is version of JSON report schema. It is incremented
+// whenever schema changes in backward incompatible way.
+const JSONReportVersion = 1
+
+type jsonReport struct {
+	Version    int                `json:"version"`
+	Pass       bool               `json:"pass"`
+	Thresholds jsonThresholds     `json:"thresholds"`
+	Checks     []jsonCheck        `json:"checks"`
+	Total      jsonStats          `json:"total"`
+	Files      []jsonStats        `json:"files"`
+	Packages   []jsonStats        `json:"packages"`
+	Functions  []jsonStats        `json:"functions-below-threshold"`
+	NewCode    *jsonNewCode       `json:"new-code,omitempty"`
+	Diff       *jsonDiff          `json:"diff,omitempty"`
+	Ratchet    []jsonRatchet      `json:"ratchet-regressions,omitempty"`
+	ColdBlocks []jsonColdBlocks   `json:"cold-blocks,omitempty"`
+	Missing    []jsonMissingNotes `json:"missing-explanations"`
+	Unmatched  []jsonMissingNotes `json:"unmatched-annotations,omitempty"`
+	Expired    []jsonMissingNotes `json:"expired-annotations,omitempty"`
+	Invalid    []jsonMissingNotes `json:"invalid-annotations,omitempty"`
+	NoIssue    []jsonMissingNotes `json:"missing-issues,omitempty"`
+	Excluded   []jsonExclusion    `json:"excluded,omitempty"`
+}
+
+type jsonThresholds struct {
+	File     float64  `json:"file"`
+	Package  float64  `json:"package"`
+	Function float64  `json:"function"`
+	Total    float64  `json:"total"`
+	NewCode  float64  `json:"new-code"`
+	Diff     *float64 `json:"diff,omitempty"`
+
+	MaxUncovered      *jsonMaxUncovered `json:"max-uncovered,omitempty"`
+	MaxUncoveredLines *jsonMaxUncovered `json:"max-uncovered-lines,omitempty"`
+}
+
+type jsonMaxUncovered struct {
+	File    int `json:"file"`
+	Package int `json:"package"`
+	Total   int `json:"total"`
+}
+
+type jsonCheck struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	Pass    bool   `json:"pass"`
+}
+
+type jsonStats struct {
+	Name              string          `json:"name"`
+	Covered           int64           `json:"covered"`
+	Total             int64           `json:"total"`
+	Ignored           int64           `json:"ignored"`
+	Percentage        float64         `json:"percentage"`
+	Threshold         float64         `json:"threshold"`
+	MaxUncovered      int             `json:"max-uncovered,omitempty"`
+	MaxUncoveredLines int             `json:"max-uncovered-lines,omitempty"`
+	Pass              bool            `json:"pass"`
+	UncoveredLines    []jsonLineRange `json:"uncovered-lines,omitempty"`
+}
+
+type jsonLineRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+type jsonNewCode struct {
+	jsonStats
+	Files []jsonStats `json:"files"`
+}
+
+type jsonDiff struct {
+	Percentage float64         `json:"percentage"`
+	Pass       bool            `json:"pass"`
+	Files      []jsonDiffEntry `json:"files"`
+}
+
+type jsonDiffEntry struct {
+	Name                string          `json:"name"`
+	Current             jsonStats       `json:"current"`
+	Base                *jsonStats      `json:"base"`
+	NewlyUncoveredLines []jsonLineRange `json:"newly-uncovered-lines,omitempty"`
+	NewlyCoveredLines   []jsonLineRange `json:"newly-covered-lines,omitempty"`
+}
+
+type jsonRatchet struct {
+	Name    string    `json:"name"`
+	Current jsonStats `json:"current"`
+	Stored  jsonStats `json:"stored"`
+}
+
+type jsonColdBlocks struct {
+	Name   string          `json:"name"`
+	Blocks []jsonColdBlock `json:"blocks"`
+}
+
+type jsonColdBlock struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+	Hits  int `json:"hits"`
+}
+
+type jsonMissingNotes struct {
+	Name  string `json:"name"`
+	Lines []int  `json:"lines"`
+}
+
+type jsonExclusion struct {
+	Name   string `json:"name"`
+	By     string `json:"excluded"`
+	Reason string `json:"reason"`
+}
+
+// MarshalJSON encodes analyze result as JSON report, which has stable schema
+// versioned with JSONReportVersion.
+//
+//nolint:gocritic // hugeParam: value receiver so that values are encoded with schema
+func (r AnalyzeResult) MarshalJSON() ([]byte, error) {
+	//nolint:wrapcheck // relax
+	return json.Marshal(makeJSONReport(r))
+}
+
+// ReportForJSON writes analyze result as indented JSON report.
+func ReportForJSON(w io.Writer, result AnalyzeResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(makeJSONReport(result)); err != nil {
+		return fmt.Errorf("encoding json report: %w", err)
+	}
+
+	return nil
+}
+
+func makeJSONReport(r AnalyzeResult) jsonReport {
+	thr := r.Threshold
+
+	files := slices.Clone(r.Files)
+	coverage.SortStatsByName(files)
+
+	report := jsonReport{
+		Version: JSONReportVersion,
+		Pass:    r.Pass(),
+		Thresholds: jsonThresholds{
+			File:     thr.File,
+			Package:  thr.Package,
+			Function: thr.Function,
+			Total:    thr.Total,
+			NewCode:  thr.NewCode,
+			Diff:     r.DiffThreshold,
+		},
+		Checks: []jsonCheck{
+			{"file", r.HasFileThreshold(), len(r.FilesBelowThreshold) == 0},
+			{"package", r.HasPackageThreshold(), len(r.PackagesBelowThreshold) == 0},
+			{"function", thr.Function > 0 || r.HasFunctionOverrides, len(r.FunctionsBelowThreshold) == 0},
+			{"total", r.HasTotalThreshold(), r.MeetsTotalCoverage()},
+			{"new-code", thr.NewCode > 0 && r.HasNewCode, r.MeetsNewCodeThreshold()},
+			{"diff", r.DiffThreshold != nil && r.HasBaseBreakdown, r.MeetsDiffThreshold()},
+			{"explanations", r.ForceAnnotationComment, len(r.FilesWithMissingExplanations) == 0},
+			{"issues", r.ForceAnnotationIssue, len(r.FilesWithMissingIssues) == 0},
+			{"max-ignored", r.MaxIgnored != MaxIgnored{}, r.MeetsMaxIgnored()},
+			{"ratchet", r.HasRatchet, len(r.RatchetRegressions) == 0},
+			{"unmatched-annotations", true, len(r.FilesWithUnmatchedAnnotations) == 0},
+			{"expired-annotations", true, len(r.FilesWithExpiredAnnotations) == 0},
+			{"invalid-annotations", true, len(r.FilesWithInvalidAnnotations) == 0},
+		},
+		Total:     makeJSONStats(r.TotalStats),
+		Files:     makeJSONStatsList(files),
+		Packages:  makeJSONStatsList(r.Packages),
+		Functions: makeJSONStatsList(r.FunctionsBelowThreshold),
+		Missing:   []jsonMissingNotes{},
+	}
+	report.Total.Threshold = thr.Total
+	report.Total.MaxUncovered = r.MaxUncovered.Total
+	report.Total.MaxUncoveredLines = r.MaxUncoveredLines.Total
+	report.Total.Pass = r.MeetsTotalCoverage()
+
+	report.Thresholds.MaxUncovered = makeJSONMaxUncovered(r.MaxUncovered)
+	report.Thresholds.MaxUncoveredLines = makeJSONMaxUncovered(r.MaxUncoveredLines)
+
+	if r.HasNewCode {
+		total := coverage.StatsCalcTotal(r.NewCode)
+		total.Threshold = thr.NewCode
+
+		report.NewCode = &jsonNewCode{
+			jsonStats: makeJSONStats(total),
+			Files:     makeJSONStatsList(r.NewCode),
+		}
+		report.NewCode.Pass = r.MeetsNewCodeThreshold()
+	}
+
+	if r.HasBaseBreakdown {
+		report.Diff = &jsonDiff{
+			Percentage: r.DiffPercentage,
+			Pass:       r.MeetsDiffThreshold(),
+			Files:      makeJSONDiff(r.Diff),
+		}
+	}
+
+	for _, rr := range r.RatchetRegressions {
+		report.Ratchet = append(report.Ratchet, jsonRatchet{
+			Name:    rr.Current.Name,
+			Current: makeJSONStats(rr.Current),
+			Stored:  makeJSONStats(rr.Stored),
+		})
+	}
+
+	for _, s := range r.FilesWithColdBlocks {
+		cb := jsonColdBlocks{Name: s.Name}
+		for _, b := range s.Blocks {
+			cb.Blocks = append(cb.Blocks, jsonColdBlock{b.StartLine, b.EndLine, b.Count})
+		}
+
+		report.ColdBlocks = append(report.ColdBlocks, cb)
+	}
+
+	for _, s := range r.FilesWithMissingExplanations {
+		report.Missing = append(report.Missing, jsonMissingNotes{
+			Name:  s.Name,
+			Lines: s.AnnotationsWithoutComments,
+		})
+	}
+
+	for _, e := range sortedExclusions(r.FilesExcludedBySource) {
+		report.Excluded = append(report.Excluded, jsonExclusion(e))
+	}
+
+	for _, s := range r.FilesWithUnmatchedAnnotations {
+		report.Unmatched = append(report.Unmatched, jsonMissingNotes{
+			Name:  s.Name,
+			Lines: s.UnmatchedAnnotations,
+		})
+	}
+
+	for _, s := range r.FilesWithExpiredAnnotations {
+		report.Expired = append(report.Expired, jsonMissingNotes{
+			Name:  s.Name,
+			Lines: s.ExpiredAnnotations,
+		})
+	}
+
+	for _, s := range r.FilesWithInvalidAnnotations {
+		report.Invalid = append(report.Invalid, jsonMissingNotes{
+			Name:  s.Name,
+			Lines: s.InvalidAnnotations,
+		})
+	}
+
+	for _, s := range r.FilesWithMissingIssues {
+		report.NoIssue = append(report.NoIssue, jsonMissingNotes{
+			Name:  s.Name,
+			Lines: s.AnnotationsWithoutIssue,
+		})
+	}
+
+	return report
+}
+
+func makeJSONStats(s coverage.Stats) jsonStats {
+	return jsonStats{
+		Name:              s.Name,
+		Covered:           s.Covered,
+		Total:             s.Total,
+		Ignored:           s.Ignored,
+		Percentage:        s.CoveredPercentageFNR(),
+		Threshold:         s.Threshold,
+		MaxUncovered:      s.MaxUncovered,
+		MaxUncoveredLines: s.MaxUncoveredLines,
+		Pass:              meetsThreshold(s),
+		UncoveredLines:    makeJSONLineRanges(s.UncoveredLines),
+	}
+}
+
+func makeJSONMaxUncovered(mu MaxUncovered) *jsonMaxUncovered {
+	if mu == (MaxUncovered{}) {
+		return nil
+	}
+
+	return &jsonMaxUncovered{
+		File:    mu.File,
+		Package: mu.Package,
+		Total:   mu.Total,
+	}
+}
+
+func makeJSONStatsList(stats []coverage.Stats) []jsonStats {
+	res := make([]jsonStats, len(stats))
+	for i, s := range stats {
+		res[i] = makeJSONStats(s)
+	}
+
+	return res
+}
+
+func makeJSONLineRanges(lines []int) []jsonLineRange {
+	var res []jsonLineRange
+	for _, r := range lineRanges(lines) {
+		res = append(res, jsonLineRange{Start: r[0], End: r[1]})
+	}
+
+	return res
+}
+
+func makeJSONDiff(diff []FileCoverageDiff) []jsonDiffEntry {
+	res := make([]jsonDiffEntry, len(diff))
+
+	for i, d := range diff {
+		e := jsonDiffEntry{
+			Name:                d.Current.Name,
+			Current:             makeJSONStats(d.Current),
+			NewlyUncoveredLines: makeJSONLineRanges(d.NewlyUncoveredLines),
+			NewlyCoveredLines:   makeJSONLineRanges(d.NewlyCoveredLines),
+		}
+
+		if d.Base != nil {
+			base := makeJSONStats(*d.Base)
+			e.Base = &base
+		}
+
+		res[i] = e
+	}
+
+	return res
+}
@@ -0,0 +1,209 @@
+package testcoverage
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/coverage"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// ReportForJUnit writes result of each enabled threshold check as JUnit XML report,
+// where every checked file, package, function and total coverage is a testcase that
+// passes or fails, so coverage checks can be tracked next to regular test results.
+func ReportForJUnit(w io.Writer, result AnalyzeResult) error {
+	suites := junitTestSuites{Name: "go-test-coverage"}
+
+	for _, s := range makeJUnitSuites(result) {
+		suites.Tests += s.Tests
+		suites.Failures += s.Failures
+		suites.Suites = append(suites.Suites, s)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("writing junit header: %w", err)
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(suites); err != nil {
+		return fmt.Errorf("encoding junit report: %w", err)
+	}
+
+	return nil
+}
+
+func makeJUnitSuites(result AnalyzeResult) []junitTestSuite {
+	var res []junitTestSuite
+
+	thr := result.Threshold
+
+	if result.HasFileThreshold() {
+		res = append(res, makeJUnitStatsSuite("file", result.Files))
+	}
+
+	if result.HasPackageThreshold() {
+		res = append(res, makeJUnitStatsSuite("package", result.Packages))
+	}
+
+	if thr.Function > 0 || result.HasFunctionOverrides {
+		res = append(res, makeJUnitStatsSuite("function", result.Functions))
+	}
+
+	if result.HasTotalThreshold() {
+		total := result.TotalStats
+		total.Name = "total"
+		total.Threshold = thr.Total
+		total.MaxUncovered = result.MaxUncovered.Total
+		total.MaxUncoveredLines = result.MaxUncoveredLines.Total
+
+		res = append(res, makeJUnitSuite("total", makeJUnitStatsCase("total", total)))
+	}
+
+	if thr.NewCode > 0 && result.HasNewCode {
+		newCode := coverage.StatsCalcTotal(result.NewCode)
+		newCode.Name = "changed lines"
+		newCode.Threshold = thr.NewCode
+
+		tc := makeJUnitStatsCase("new-code", newCode)
+		if tc.Failure != nil {
+			tc.Failure.Text = junitUncoveredLines(result.NewCode)
+		}
+
+		res = append(res, makeJUnitSuite("new-code", tc))
+	}
+
+	if result.DiffThreshold != nil && result.HasBaseBreakdown {
+		tc := junitTestCase{Name: "coverage difference", ClassName: "coverage.diff"}
+		if !result.MeetsDiffThreshold() {
+			tc.Failure = &junitFailure{
+				Type: "coverage",
+				Message: fmt.Sprintf("coverage difference: %.2f%%; threshold: %.2f%%",
+					result.DiffPercentage, *result.DiffThreshold),
+			}
+		}
+
+		res = append(res, makeJUnitSuite("diff", tc))
+	}
+
+	if result.ForceAnnotationComment {
+		res = append(res, makeJUnitExplanationsSuite(result))
+	}
+
+	return res
+}
+
+func makeJUnitSuite(name string, cases ...junitTestCase) junitTestSuite {
+	s := junitTestSuite{
+		Name:      "coverage." + name,
+		Tests:     len(cases),
+		TestCases: cases,
+	}
+
+	for _, c := range cases {
+		if c.Failure != nil {
+			s.Failures++
+		}
+	}
+
+	return s
+}
+
+func makeJUnitStatsSuite(name string, stats []coverage.Stats) junitTestSuite {
+	stats = slices.Clone(stats)
+	coverage.SortStatsByName(stats)
+
+	cases := make([]junitTestCase, len(stats))
+	for i, s := range stats {
+		cases[i] = makeJUnitStatsCase(name, s)
+	}
+
+	return makeJUnitSuite(name, cases...)
+}
+
+func makeJUnitStatsCase(name string, s coverage.Stats) junitTestCase {
+	msg := fmt.Sprintf("coverage: %s; threshold: %s",
+		s.Str(), statsThresholdStr(s),
+	)
+
+	tc := junitTestCase{
+		Name:      s.Name,
+		ClassName: "coverage." + name,
+		SystemOut: msg,
+	}
+
+	if !meetsThreshold(s) {
+		tc.Failure = &junitFailure{Message: msg, Type: "coverage"}
+		if len(s.UncoveredLines) > 0 {
+			tc.Failure.Text = "uncovered lines: " + formatLines(s.UncoveredLines)
+		}
+	}
+
+	return tc
+}
+
+func makeJUnitExplanationsSuite(result AnalyzeResult) junitTestSuite {
+	files := slices.Clone(result.Files)
+	coverage.SortStatsByName(files)
+
+	missing := coverage.StatsSearchMap(result.FilesWithMissingExplanations)
+	cases := make([]junitTestCase, 0, len(files))
+
+	for _, f := range files {
+		tc := junitTestCase{Name: f.Name, ClassName: "coverage.explanations"}
+
+		if s, ok := missing[f.Name]; ok {
+			tc.Failure = &junitFailure{
+				Type:    "explanations",
+				Message: "missing explanation for coverage-ignore",
+				Text: fmt.Sprintf("add an explanation after the coverage-ignore annotation on lines: %s",
+					formatLines(s.AnnotationsWithoutComments)),
+			}
+		}
+
+		cases = append(cases, tc)
+	}
+
+	return makeJUnitSuite("explanations", cases...)
+}
+
+func junitUncoveredLines(stats []coverage.Stats) string {
+	sb := &strings.Builder{}
+
+	for _, s := range coverage.StatsFilterWithUncoveredLines(stats) {
+		fmt.Fprintf(sb, "%s: %s\n", s.Name, formatLines(s.UncoveredLines))
+	}
+
+	return sb.String()
+}
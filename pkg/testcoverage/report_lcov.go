@@ -0,0 +1,90 @@
+package testcoverage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/coverage"
+)
+
+// ReportForLcov writes coverage report in LCOV tracefile format. Report is made
+// from analyzed stats, so excluded files and code ignored with annotations are not
+// part of it, which keeps it in line with what threshold check enforces.
+func ReportForLcov(w io.Writer, result AnalyzeResult) error {
+	bw := bufio.NewWriter(w)
+
+	files := slices.Clone(result.Files)
+	coverage.SortStatsByName(files)
+
+	for _, s := range files {
+		writeLcovRecord(bw, s)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("writing lcov report: %w", err)
+	}
+
+	return nil
+}
+
+func writeLcovRecord(w io.Writer, s coverage.Stats) {
+	hits := s.LineHits()
+
+	sourceFile := s.Path
+	if sourceFile == "" {
+		sourceFile = s.Name
+	}
+
+	fmt.Fprintf(w, "TN:\n")
+	fmt.Fprintf(w, "SF:%s\n", sourceFile)
+
+	for _, fn := range s.Functions {
+		fmt.Fprintf(w, "FN:%d,%s\n", fn.StartLine, fn.Name)
+	}
+
+	fnHit := 0
+
+	for _, fn := range s.Functions {
+		count := funcHits(hits, fn)
+		if count > 0 {
+			fnHit++
+		}
+
+		fmt.Fprintf(w, "FNDA:%d,%s\n", count, fn.Name)
+	}
+
+	fmt.Fprintf(w, "FNF:%d\n", len(s.Functions))
+	fmt.Fprintf(w, "FNH:%d\n", fnHit)
+
+	linesHit := 0
+
+	for _, h := range hits {
+		if h.Hits > 0 {
+			linesHit++
+		}
+
+		fmt.Fprintf(w, "DA:%d,%d\n", h.Line, h.Hits)
+	}
+
+	fmt.Fprintf(w, "LF:%d\n", len(hits))
+	fmt.Fprintf(w, "LH:%d\n", linesHit)
+	fmt.Fprintf(w, "end_of_record\n")
+}
+
+// funcHits returns number of times function was executed, which is
+// hit count of the first line of function body.
+func funcHits(hits []coverage.LineHit, fn coverage.FuncStats) int {
+	if fn.Covered == 0 {
+		return 0
+	}
+
+	for _, h := range hits {
+		if h.Line >= fn.StartLine && h.Line <= fn.EndLine {
+			return max(h.Hits, 1)
+		}
+	}
+
+	return 1
+}
@@ -0,0 +1,282 @@
+package testcoverage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/coverage"
+)
+
+const gaStepSummaryEnv = "GITHUB_STEP_SUMMARY"
+
+// ReportForMarkdown writes coverage report in GitHub flavored markdown. Report holds
+// status of each threshold check, collapsible sections with files below threshold
+// and uncovered lines, and coverage difference compared to the base breakdown.
+// When blobURL is set, files and uncovered lines are linked to the source at blobURL,
+// where blobURL points to the root of repository.
+func ReportForMarkdown(w io.Writer, result AnalyzeResult, blobURL string) {
+	if blobURL != "" {
+		blobURL += moduleDirInRepo(result.Files)
+	}
+
+	out := bufio.NewWriter(w)
+	defer out.Flush()
+
+	fmt.Fprintf(out, "## Test coverage report\n")
+
+	markdownThresholds(out, result)
+
+	fmt.Fprintf(out, "\n**Total test coverage:** %s\n", result.TotalStats.Str())
+
+	markdownStatsBelowThreshold(out, "Files below threshold", "file",
+		result.FilesBelowThreshold, blobURL)
+	markdownStatsBelowThreshold(out, "Packages below threshold", "package",
+		result.PackagesBelowThreshold, "")
+	markdownStatsBelowThreshold(out, "Functions below threshold", "function",
+		result.FunctionsBelowThreshold, "")
+	markdownUncoveredLines(out, result, blobURL)
+	markdownExcludedBySource(out, result, blobURL)
+	markdownDiff(out, result, blobURL)
+}
+
+func markdownThresholds(w io.Writer, result AnalyzeResult) {
+	thresholds := makeThresholdChecks(result)
+	if len(thresholds) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n| check | threshold | status |\n")
+	fmt.Fprintf(w, "|---|---|---|\n")
+
+	for _, t := range thresholds {
+		fmt.Fprintf(w, "| %s | %s | %s |\n", t.Name, t.Value, markdownStatus(t.Pass))
+	}
+}
+
+func markdownStatsBelowThreshold(
+	w io.Writer,
+	title, kind string,
+	stats []coverage.Stats,
+	blobURL string,
+) {
+	if len(stats) == 0 {
+		return
+	}
+
+	stats = sortedStats(stats)
+
+	fmt.Fprintf(w, "\n<details>\n<summary>%s (%d)</summary>\n\n", title, len(stats))
+	fmt.Fprintf(w, "| %s | coverage | threshold |\n", kind)
+	fmt.Fprintf(w, "|---|---|---|\n")
+
+	for _, s := range stats {
+		fmt.Fprintf(w, "| %s | %s | %s |\n",
+			markdownFileLink(s.Name, blobURL), s.Str(), statsThresholdStr(s),
+		)
+	}
+
+	fmt.Fprintf(w, "\n</details>\n")
+}
+
+func markdownUncoveredLines(w io.Writer, result AnalyzeResult, blobURL string) {
+	stats := sortedStats(coverage.StatsFilterWithUncoveredLines(result.FilesWithUncoveredLines))
+	if len(stats) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n<details>\n<summary>Files with uncovered lines (%d)</summary>\n\n", len(stats))
+	fmt.Fprintf(w, "| file | coverage | uncovered lines |\n")
+	fmt.Fprintf(w, "|---|---|---|\n")
+
+	for _, s := range stats {
+		fmt.Fprintf(w, "| %s | %s | %s |\n",
+			markdownFileLink(s.Name, blobURL),
+			s.Str(),
+			markdownLineRanges(s.Name, s.UncoveredLines, blobURL),
+		)
+	}
+
+	fmt.Fprintf(w, "\n</details>\n")
+}
+
+func markdownExcludedBySource(w io.Writer, result AnalyzeResult, blobURL string) {
+	if len(result.FilesExcludedBySource) == 0 {
+		return
+	}
+
+	exclusions := sortedExclusions(result.FilesExcludedBySource)
+
+	fmt.Fprintf(w, "\n<details>\n<summary>Files excluded by source (%d)</summary>\n\n", len(exclusions))
+	fmt.Fprintf(w, "| file | excluded | reason |\n")
+	fmt.Fprintf(w, "|---|---|---|\n")
+
+	for _, e := range exclusions {
+		fmt.Fprintf(w, "| %s | `%s` | %s |\n", markdownFileLink(e.Name, blobURL), e.By, e.Reason)
+	}
+
+	fmt.Fprintf(w, "\n</details>\n")
+}
+
+func markdownDiff(w io.Writer, result AnalyzeResult, blobURL string) {
+	if !result.HasBaseBreakdown {
+		return
+	}
+
+	fmt.Fprintf(w, "\n### Coverage difference compared to the base\n\n")
+	fmt.Fprintf(w, "Coverage difference: %.2f%%\n", result.DiffPercentage)
+
+	if len(result.Diff) == 0 {
+		fmt.Fprintf(w, "\nNo coverage changes in any files compared to the base.\n")
+		return
+	}
+
+	td := TotalLinesMissingCoverage(result.Diff)
+	fmt.Fprintf(w, "\nTest coverage has changed in the current files, with %d lines missing coverage.\n\n", td)
+	fmt.Fprintf(w, "| file | uncovered | current coverage | base coverage | newly uncovered lines |\n")
+	fmt.Fprintf(w, "|---|---|---|---|---|\n")
+
+	for _, d := range result.Diff {
+		baseStr := "/" // no base coverage for this file
+		if d.Base != nil {
+			baseStr = d.Base.Str()
+		}
+
+		c := d.Current
+		fmt.Fprintf(w, "| %s | %d | %s | %s | %s |\n",
+			markdownFileLink(c.Name, blobURL), c.UncoveredLinesCount(), c.Str(), baseStr,
+			markdownLineRanges(c.Name, d.NewlyUncoveredLines, blobURL))
+	}
+}
+
+func markdownStatus(pass bool) string {
+	if pass {
+		return ":white_check_mark: " + statusStr(pass)
+	}
+
+	return ":x: " + statusStr(pass)
+}
+
+func markdownFileLink(name, blobURL string) string {
+	if blobURL == "" {
+		return "`" + name + "`"
+	}
+
+	return fmt.Sprintf("[`%s`](%s%s)", name, blobURL, name)
+}
+
+func markdownLineRanges(name string, lines []int, blobURL string) string {
+	ranges := lineRanges(lines)
+	res := make([]string, len(ranges))
+
+	for i, r := range ranges {
+		text, anchor := strconv.Itoa(r[0]), "#L"+strconv.Itoa(r[0])
+		if r[0] != r[1] {
+			text += "-" + strconv.Itoa(r[1])
+			anchor += "-L" + strconv.Itoa(r[1])
+		}
+
+		if blobURL == "" {
+			res[i] = text
+		} else {
+			res[i] = fmt.Sprintf("[%s](%s%s%s)", text, blobURL, name, anchor)
+		}
+	}
+
+	return strings.Join(res, " ")
+}
+
+// moduleDirInRepo returns directory of module relative to the root of git repository,
+// with trailing slash, or empty string when module is at the root of repository.
+// File names are relative to module, so this directory is needed to make links to
+// files when go.mod is not at the root of repository.
+func moduleDirInRepo(files []coverage.Stats) string {
+	for _, s := range files {
+		name := filepath.FromSlash(s.Name)
+		if s.Path == "" || !strings.HasSuffix(s.Path, name) {
+			continue
+		}
+
+		moduleDir, err := filepath.Abs(strings.TrimSuffix(s.Path, name))
+		if err != nil { // coverage-ignore
+			return ""
+		}
+
+		repoDir := findRepoRoot(moduleDir)
+		if repoDir == "" {
+			return ""
+		}
+
+		rel, err := filepath.Rel(repoDir, moduleDir)
+		if err != nil { // coverage-ignore
+			return ""
+		}
+
+		if rel == "." {
+			return "" // module is at the root of repository
+		}
+
+		return filepath.ToSlash(rel) + "/"
+	}
+
+	return ""
+}
+
+// findRepoRoot returns first directory, starting from dir and moving up, which
+// holds .git entry. Empty string is returned when there is no such directory.
+func findRepoRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+
+		dir = parent
+	}
+}
+
+// githubBlobURL returns URL of repository source at the commit of GitHub workflow run,
+// or empty string when it is not run in GitHub workflow.
+func githubBlobURL() string {
+	server := os.Getenv("GITHUB_SERVER_URL")
+	repo := os.Getenv("GITHUB_REPOSITORY")
+	sha := os.Getenv("GITHUB_SHA")
+
+	if server == "" || repo == "" || sha == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s/%s/blob/%s/", server, repo, sha)
+}
+
+// SetGithubStepSummary appends markdown report to GitHub job summary. It does
+// nothing when summary file is not available.
+func SetGithubStepSummary(result AnalyzeResult) error {
+	p := os.Getenv(gaStepSummaryEnv)
+	if p == "" {
+		return nil
+	}
+
+	file, err := openGitHubOutput(p)
+	if err != nil {
+		return fmt.Errorf("could not open GitHub step summary file: %w", err)
+	}
+
+	out := bufio.NewWriter(file)
+	ReportForMarkdown(out, result, githubBlobURL())
+
+	if err := out.Flush(); err != nil { // coverage-ignore
+		file.Close()
+		return fmt.Errorf("writing GitHub step summary: %w", err)
+	}
+
+	return file.Close() //nolint:wrapcheck // relax
+}
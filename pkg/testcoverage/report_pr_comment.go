@@ -0,0 +1,39 @@
+package testcoverage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/logger"
+	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/prcomment"
+)
+
+// ReportForPRComment creates or updates single comment on pull request with
+// markdown report, which includes coverage difference compared to the base
+// when base breakdown is available. Comment is skipped when pull request number is
+// not set, which is the case when workflow is not triggered by pull request event.
+func ReportForPRComment(w io.Writer, result AnalyzeResult, cfg prcomment.Github) error {
+	if cfg.PullRequest == 0 {
+		logger.L.Info().Msg("pull request comment skipped: pull request number is not set")
+		fmt.Fprintf(w, "\nPull request comment skipped, because pull request number is not set.\n")
+
+		return nil
+	}
+
+	body := &bytes.Buffer{}
+	ReportForMarkdown(body, result, githubBlobURL())
+
+	changed, err := prcomment.Upsert(cfg, body.String())
+	if err != nil {
+		return fmt.Errorf("comment on pull request: %w", err)
+	}
+
+	if changed {
+		fmt.Fprintf(w, "\nPull request #%d commented with coverage report.\n", cfg.PullRequest)
+	} else {
+		fmt.Fprintf(w, "\nPull request #%d already has comment with the same coverage report.\n", cfg.PullRequest)
+	}
+
+	return nil
+}
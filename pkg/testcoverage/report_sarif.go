@@ -0,0 +1,187 @@
+package testcoverage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+const (
+	sarifVersion   = "2.1.0"
+	sarifSchema    = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifToolURI   = "https://github.com/vladopajic/go-test-coverage"
+	sarifURIBaseID = "%SRCROOT%"
+)
+
+const (
+	sarifRuleFileThreshold       = "file-coverage-below-threshold"
+	sarifRulePackageThreshold    = "package-coverage-below-threshold"
+	sarifRuleFunctionThreshold   = "function-coverage-below-threshold"
+	sarifRuleTotalThreshold      = "total-coverage-below-threshold"
+	sarifRuleNewCodeThreshold    = "changed-lines-not-covered"
+	sarifRuleMissingExplanation  = "missing-coverage-ignore-explanation"
+	sarifRuleUnmatchedAnnotation = "unmatched-coverage-ignore-annotation"
+	sarifRuleExpiredAnnotation   = "expired-coverage-ignore-annotation"
+	sarifRuleInvalidAnnotation   = "invalid-coverage-ignore-annotation"
+	sarifRuleMissingIssue        = "missing-coverage-ignore-issue"
+	sarifRuleUncoveredLines      = "uncovered-lines"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine,omitempty"`
+}
+
+//nolint:gochecknoglobals // relax
+var sarifRules = []sarifRule{
+	makeSarifRule(sarifRuleFileThreshold, "File test coverage below threshold", "error"),
+	makeSarifRule(sarifRulePackageThreshold, "Package test coverage below threshold", "error"),
+	makeSarifRule(sarifRuleFunctionThreshold, "Function test coverage below threshold", "error"),
+	makeSarifRule(sarifRuleTotalThreshold, "Total test coverage below threshold", "error"),
+	makeSarifRule(sarifRuleNewCodeThreshold, "Changed lines not covered by tests", "error"),
+	makeSarifRule(sarifRuleMissingExplanation, "Missing explanation for coverage-ignore", "error"),
+	makeSarifRule(sarifRuleUnmatchedAnnotation,
+		"Unmatched coverage-ignore range annotation", "error"),
+	makeSarifRule(sarifRuleExpiredAnnotation, "Expired coverage-ignore annotation", "error"),
+	makeSarifRule(sarifRuleInvalidAnnotation, "Invalid coverage-ignore annotation", "error"),
+	makeSarifRule(sarifRuleMissingIssue, "Missing issue reference for coverage-ignore", "error"),
+	makeSarifRule(sarifRuleUncoveredLines, "Lines not covered by tests", "note"),
+}
+
+func makeSarifRule(id, description, level string) sarifRule {
+	return sarifRule{
+		ID:                   id,
+		ShortDescription:     sarifMessage{Text: description},
+		DefaultConfiguration: sarifConfiguration{Level: level},
+	}
+}
+
+// ReportForSarif writes issues found by analysis as SARIF 2.1.0 log, which can be
+// uploaded to GitHub code scanning or consumed by other SARIF tools. Issues are the
+// same as ones reported by ReportForGithubAction. When withUncoveredLines is set,
+// each range of uncovered lines is additionally reported as note.
+func ReportForSarif(w io.Writer, result AnalyzeResult, withUncoveredLines bool) error {
+	log := sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "go-test-coverage",
+				Version:        toolVersion(),
+				InformationURI: sarifToolURI,
+				Rules:          sarifRules,
+			}},
+			Results: makeSarifResults(result, withUncoveredLines),
+		}},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(log); err != nil {
+		return fmt.Errorf("encoding sarif report: %w", err)
+	}
+
+	return nil
+}
+
+func makeSarifResults(result AnalyzeResult, withUncoveredLines bool) []sarifResult {
+	res := []sarifResult{}
+
+	add := func(ruleID, level, msg string, loc sarifLocation) {
+		res = append(res, sarifResult{
+			RuleID:    ruleID,
+			Level:     level,
+			Message:   sarifMessage{Text: msg},
+			Locations: []sarifLocation{loc},
+		})
+	}
+
+	for _, i := range reportIssues(result) {
+		add(i.id, "error", i.msg, sarifLocationFor(i.file, i.start, i.end))
+	}
+
+	if withUncoveredLines {
+		for _, stats := range sortedStats(result.FilesWithUncoveredLines) {
+			for _, r := range lineRanges(stats.UncoveredLines) {
+				add(sarifRuleUncoveredLines, "note", "Lines not covered by tests",
+					sarifLocationFor(stats.Name, r[0], r[1]))
+			}
+		}
+	}
+
+	return res
+}
+
+// sarifLocationFor returns location of file. Region is set only when startLine
+// is specified.
+func sarifLocationFor(name string, startLine, endLine int) sarifLocation {
+	loc := sarifLocation{
+		PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: name, URIBaseID: sarifURIBaseID},
+		},
+	}
+
+	if startLine > 0 {
+		loc.PhysicalLocation.Region = &sarifRegion{StartLine: startLine, EndLine: endLine}
+	}
+
+	return loc
+}
@@ -2,15 +2,24 @@ package testcoverage_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"encoding/xml"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	. "github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage"
+	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/checkrun"
 	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/coverage"
+	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/prcomment"
 )
 
 func Test_ReportForHuman(t *testing.T) {
@@ -18,7 +27,7 @@ func Test_ReportForHuman(t *testing.T) {
 
 	const prefix = "organization.org"
 
-	thr := Threshold{100, 100, 100}
+	thr := Threshold{File: 100, Package: 100, Total: 100}
 
 	t.Run("all - pass", func(t *testing.T) {
 		t.Parallel()
@@ -87,6 +96,123 @@ func Test_ReportForHuman(t *testing.T) {
 			coverage.StatsPluckName(coverage.StatsFilterWithCoveredLines(allStats)),
 		)
 	})
+
+	t.Run("fractional threshold", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		stats := []coverage.Stats{{Name: prefix + "/foo.go", Total: 1000, Covered: 874}}
+		result := Analyze(Config{Threshold: Threshold{File: 87.5, Total: 87.5}}, stats, nil)
+		ReportForHuman(buf, result)
+
+		assertHumanReport(t, buf.String(), 0, 2)
+		assert.Contains(t, buf.String(), "File coverage threshold (87.5%) satisfied:")
+		assert.Contains(t, buf.String(), "Total coverage threshold (87.5%) satisfied:")
+		assert.Contains(t, buf.String(), "87.4% (874/1000)\t87.5%")
+	})
+
+	t.Run("max uncovered", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		stats := []coverage.Stats{
+			{Name: prefix + "/a/foo.go", Total: 1000, Covered: 990},
+			{Name: prefix + "/b/bar.go", Total: 10, Covered: 4},
+		}
+		result := Analyze(Config{
+			Threshold:    Threshold{File: 50},
+			MaxUncovered: MaxUncovered{File: 5, Total: 100},
+			Override:     []Override{{Path: "bar.go$", Threshold: ptr(70.0)}},
+		}, stats, nil)
+		ReportForHuman(buf, result)
+
+		out := regexp.MustCompile("\t+").ReplaceAllString(buf.String(), "\t")
+		assert.Contains(t, out, "File coverage threshold (50%, max 5 uncovered) satisfied:\tFAIL")
+		assert.Contains(t, out, "Total coverage threshold (0%, max 100 uncovered) satisfied:\tPASS")
+		assert.Contains(t, out, "below threshold:\tcoverage:\tthreshold:\tuncovered:\tmax uncovered:")
+		assert.Contains(t, out, prefix+"/a/foo.go\t99.0% (990/1000)\t50%\t10\t5")
+		assert.Contains(t, out, prefix+"/b/bar.go\t40.0% (4/10)\t70%\t6\t5")
+
+		// files without limit of uncovered statements
+		buf = &bytes.Buffer{}
+		ReportForHuman(buf, Analyze(Config{
+			Threshold: Threshold{File: 100},
+			Override:  []Override{{Path: "bar.go$", Threshold: ptr(70.0), MaxUncovered: ptr(2)}},
+		}, stats, nil))
+
+		out = regexp.MustCompile("\t+").ReplaceAllString(buf.String(), "\t")
+		assert.Contains(t, out, prefix+"/a/foo.go\t99.0% (990/1000)\t100%\t10\t-")
+		assert.Contains(t, out, prefix+"/b/bar.go\t40.0% (4/10)\t70%\t6\t2")
+
+		buf = &bytes.Buffer{}
+		assert.NoError(t, ReportForJSON(buf, result))
+		assert.Contains(t, buf.String(), `"max-uncovered": 5`)
+		assert.Contains(t, buf.String(), `"max-uncovered": {`)
+		assert.NotContains(t, buf.String(), `"max-uncovered-lines"`)
+	})
+
+	t.Run("max uncovered lines", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		stats := []coverage.Stats{
+			{Name: prefix + "/a/foo.go", Total: 10, Covered: 6, UncoveredLines: []int{1, 2, 3}},
+		}
+		result := Analyze(Config{
+			MaxUncovered:      MaxUncovered{File: 5},
+			MaxUncoveredLines: MaxUncovered{File: 2, Total: 10},
+		}, stats, nil)
+		ReportForHuman(buf, result)
+
+		out := regexp.MustCompile("\t+").ReplaceAllString(buf.String(), "\t")
+		assert.Contains(t, out,
+			"File coverage threshold (0%, max 5 uncovered, max 2 uncovered lines) satisfied:\tFAIL")
+		assert.Contains(t, out,
+			"Total coverage threshold (0%, max 10 uncovered lines) satisfied:\tPASS")
+		assert.Contains(t, out, "\tuncovered lines:\tmax uncovered lines:")
+		assert.Contains(t, out, prefix+"/a/foo.go\t60.0% (6/10)\t0%\t4\t5\t3\t2")
+
+		buf = &bytes.Buffer{}
+		assert.NoError(t, ReportForJSON(buf, result))
+		assert.Contains(t, buf.String(), `"max-uncovered-lines": 2`)
+		assert.Contains(t, buf.String(), `"max-uncovered-lines": {`)
+	})
+}
+
+func Test_ReportForHumanFunctions(t *testing.T) {
+	t.Parallel()
+
+	stats := []coverage.Stats{{
+		Name: "organization.org/foo.go", Total: 10, Covered: 5,
+		Functions: []coverage.FuncStats{
+			{Name: "Foo", Total: 5, Covered: 5},
+			{Name: "Bar", Total: 5, Covered: 0},
+		},
+	}}
+
+	t.Run("function coverage - pass", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		result := Analyze(Config{Threshold: Threshold{Function: 0}}, stats, nil)
+		ReportForHuman(buf, result)
+
+		assertHumanReport(t, buf.String(), 0, 0)
+		assert.NotContains(t, buf.String(), "Function coverage threshold")
+	})
+
+	t.Run("function coverage - fail", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		result := Analyze(Config{Threshold: Threshold{Function: 50}}, stats, nil)
+		ReportForHuman(buf, result)
+
+		assertHumanReport(t, buf.String(), 0, 1)
+		assert.Contains(t, buf.String(), "Function coverage threshold (50%) satisfied:\tFAIL")
+		assert.Contains(t, buf.String(), "organization.org/foo.go:Bar")
+		assert.NotContains(t, buf.String(), "organization.org/foo.go:Foo")
+	})
 }
 
 func Test_ReportForHumanDiff(t *testing.T) {
@@ -124,8 +250,38 @@ func Test_ReportForHumanDiff(t *testing.T) {
 		ReportForHuman(buf, result)
 
 		assertDiffChange(t, buf.String(), 2)
-		assert.Contains(t, buf.String(), "foo\t\t  1\t\t88.9% (8/9)\t\t100% (10/10)")
-		assert.Contains(t, buf.String(), "foo-new\t  1\t\t88.9% (8/9)\t")
+		assert.Contains(t, buf.String(), "foo\t\t  1\t\t88.8% (8/9)\t\t100% (10/10)")
+		assert.Contains(t, buf.String(), "foo-new\t  1\t\t88.8% (8/9)\t")
+	})
+
+	t.Run("diff - changed lines", func(t *testing.T) {
+		t.Parallel()
+
+		base := []coverage.Stats{{Name: "foo", Total: 10, Covered: 7, UncoveredLines: []int{1, 2, 3}}}
+		stats := []coverage.Stats{{Name: "foo", Total: 10, Covered: 6, UncoveredLines: []int{3, 5, 6, 7}}}
+
+		buf := &bytes.Buffer{}
+		result := Analyze(Config{}, stats, base)
+		ReportForHuman(buf, result)
+
+		assert.Contains(t, buf.String(), "Lines that became uncovered compared to the base:")
+		assert.Contains(t, buf.String(), "foo\t\t5-7")
+		assert.Contains(t, buf.String(), "Lines that became covered compared to the base:")
+		assert.Contains(t, buf.String(), "foo\t\t1-2")
+	})
+
+	t.Run("diff - legacy base breakdown", func(t *testing.T) {
+		t.Parallel()
+
+		base := []coverage.Stats{{Name: "foo", Total: 10, Covered: 7}}
+		stats := []coverage.Stats{{Name: "foo", Total: 10, Covered: 6, UncoveredLines: []int{3, 5, 6, 7}}}
+
+		buf := &bytes.Buffer{}
+		result := Analyze(Config{}, stats, base)
+		ReportForHuman(buf, result)
+
+		assert.NotContains(t, buf.String(), "Lines that became uncovered compared to the base:")
+		assert.NotContains(t, buf.String(), "Lines that became covered compared to the base:")
 	})
 
 	t.Run("diff - threshold failed", func(t *testing.T) {
@@ -183,222 +339,1815 @@ func Test_ReportForHumanDiff(t *testing.T) {
 	})
 }
 
-func Test_ReportForGithubAction(t *testing.T) {
+func Test_ReportForHumanNewCode(t *testing.T) {
 	t.Parallel()
 
-	const prefix = "organization.org/pkg/"
-
-	t.Run("total coverage - pass", func(t *testing.T) {
+	t.Run("no new code", func(t *testing.T) {
 		t.Parallel()
 
 		buf := &bytes.Buffer{}
-		cfg := Config{Threshold: Threshold{Total: 100}}
-		statsNoError := randStats(prefix, 100, 100)
-		result := Analyze(cfg, statsNoError, nil)
-		ReportForGithubAction(buf, result)
-		assertGithubActionErrorsCount(t, buf.String(), 0)
-		assertNotContainStats(t, buf.String(), statsNoError)
+		ReportForHuman(buf, AnalyzeResult{Threshold: Threshold{NewCode: 80}})
+
+		assertHumanReport(t, buf.String(), 0, 0)
+		assert.NotContains(t, buf.String(), "New code")
 	})
 
-	t.Run("total coverage - fail", func(t *testing.T) {
+	t.Run("new code - pass", func(t *testing.T) {
 		t.Parallel()
 
 		buf := &bytes.Buffer{}
-		statsWithError := randStats(prefix, 0, 9)
-		statsNoError := randStats(prefix, 10, 100)
-		cfg := Config{Threshold: Threshold{Total: 10}}
-		result := Analyze(cfg, mergeStats(statsWithError, statsNoError), nil)
-		ReportForGithubAction(buf, result)
-		assertGithubActionErrorsCount(t, buf.String(), 1)
-		assertNotContainStats(t, buf.String(), statsWithError)
-		assertNotContainStats(t, buf.String(), statsNoError)
+		ReportForHuman(buf, AnalyzeResult{
+			Threshold:  Threshold{NewCode: 80},
+			HasNewCode: true,
+			NewCode:    []coverage.Stats{makeStats("foo.go", 10, 10)},
+		})
+
+		assertHumanReport(t, buf.String(), 1, 0)
+		assert.Contains(t, buf.String(), "New code coverage threshold (80%) satisfied:\tPASS")
+		assert.Contains(t, buf.String(), "New code test coverage: 100% (10/10)")
+		assert.NotContains(t, buf.String(), "Changed lines without coverage")
 	})
 
-	t.Run("file coverage - pass", func(t *testing.T) {
+	t.Run("new code - fail", func(t *testing.T) {
 		t.Parallel()
 
 		buf := &bytes.Buffer{}
-		cfg := Config{Threshold: Threshold{File: 10}}
-		statsNoError := randStats(prefix, 10, 100)
-		result := Analyze(cfg, statsNoError, nil)
-		ReportForGithubAction(buf, result)
-		assertGithubActionErrorsCount(t, buf.String(), 0)
-		assertNotContainStats(t, buf.String(), statsNoError)
+		ReportForHuman(buf, AnalyzeResult{
+			Threshold:  Threshold{NewCode: 90},
+			HasNewCode: true,
+			NewCode: []coverage.Stats{
+				makeStats("foo.go", 10, 10),
+				makeStats("bar.go", 10, 7),
+			},
+		})
+
+		assertHumanReport(t, buf.String(), 0, 1)
+		assert.Contains(t, buf.String(), "New code test coverage: 85.0% (17/20)")
+		assert.Contains(t, buf.String(), "Changed lines without coverage")
+		assert.Contains(t, buf.String(), "bar.go\t70.0% (7/10)\t1-3")
 	})
+}
 
-	t.Run("file coverage - fail", func(t *testing.T) {
-		t.Parallel()
+func Test_ReportForHumanColdBlocks(t *testing.T) {
+	t.Parallel()
 
-		buf := &bytes.Buffer{}
-		cfg := Config{Threshold: Threshold{File: 10}}
-		statsWithError := randStats(prefix, 0, 9)
-		statsNoError := randStats(prefix, 10, 100)
-		result := Analyze(cfg, mergeStats(statsWithError, statsNoError), nil)
-		ReportForGithubAction(buf, result)
-		assertGithubActionErrorsCount(t, buf.String(), len(statsWithError))
-		assertContainStats(t, buf.String(), statsWithError)
-		assertNotContainStats(t, buf.String(), statsNoError)
-	})
+	stats := []coverage.Stats{
+		{Name: "foo.go", Total: 10, Covered: 10, Blocks: []coverage.Block{
+			{StartLine: 3, EndLine: 5, NumStmt: 2, Count: 1},
+			{StartLine: 7, EndLine: 7, NumStmt: 1, Count: 2},
+			{StartLine: 9, EndLine: 10, NumStmt: 7, Count: 100},
+		}},
+	}
 
-	t.Run("package coverage - pass", func(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
 		t.Parallel()
 
 		buf := &bytes.Buffer{}
-		cfg := Config{Threshold: Threshold{Package: 10}}
-		statsNoError := randStats(prefix, 10, 100)
-		result := Analyze(cfg, statsNoError, nil)
-		ReportForGithubAction(buf, result)
-		assertGithubActionErrorsCount(t, buf.String(), 0)
-		assertNotContainStats(t, buf.String(), MakePackageStats(statsNoError))
-		assertNotContainStats(t, buf.String(), statsNoError)
+		ReportForHuman(buf, Analyze(Config{}, stats, nil))
+		assert.NotContains(t, buf.String(), "Covered blocks executed fewer than")
 	})
 
-	t.Run("package coverage - fail", func(t *testing.T) {
+	t.Run("enabled", func(t *testing.T) {
 		t.Parallel()
 
 		buf := &bytes.Buffer{}
-		cfg := Config{Threshold: Threshold{Package: 10}}
-		statsWithError := randStats(prefix, 0, 9)
-		statsNoError := randStats(prefix, 10, 100)
-		result := Analyze(cfg, mergeStats(statsWithError, statsNoError), nil)
-		ReportForGithubAction(buf, result)
-		assertGithubActionErrorsCount(t, buf.String(), len(MakePackageStats(statsWithError)))
-		assertContainStats(t, buf.String(), MakePackageStats(statsWithError))
-		assertNotContainStats(t, buf.String(), MakePackageStats(statsNoError))
-		assertNotContainStats(t, buf.String(), statsWithError)
-		assertNotContainStats(t, buf.String(), statsNoError)
+		ReportForHuman(buf, Analyze(Config{ColdBlockHits: 3}, stats, nil))
+		assert.Contains(t, buf.String(), "Covered blocks executed fewer than 3 times:")
+		assert.Contains(t, buf.String(), "foo.go\t3-5\t1")
+		assert.Contains(t, buf.String(), "foo.go\t7\t2")
+		assert.NotContains(t, buf.String(), "9-10")
 	})
+}
 
-	t.Run("file, package and total - fail", func(t *testing.T) {
+func Test_ReportForHTML(t *testing.T) {
+	t.Parallel()
+
+	source := t.TempDir() + "/foo.go"
+	assert.NoError(t, os.WriteFile(source, []byte("package foo\n\nfunc a() {}\nfunc b() {}\n"), 0o600))
+
+	stats := []coverage.Stats{
+		{
+			Name: "org/pkg/foo.go", Path: source, Total: 10, Covered: 5,
+			CoveredLines: []int{3}, UncoveredLines: []int{4}, IgnoredLines: []int{1},
+		},
+		{Name: "org/pkg/bar.go", Path: t.TempDir() + "/missing.go", Total: 2, Covered: 2},
+		{Name: "org/other/baz.go", Total: 1, Covered: 1},
+	}
+
+	t.Run("without base", func(t *testing.T) {
 		t.Parallel()
 
+		cfg := Config{Threshold: Threshold{File: 60, Total: 50}}
+
 		buf := &bytes.Buffer{}
-		cfg := Config{Threshold: Threshold{File: 10, Package: 10, Total: 100}}
-		statsWithError := randStats(prefix, 0, 9)
-		statsNoError := randStats(prefix, 10, 100)
-		totalErrorsCount := len(MakePackageStats(statsWithError)) + len(statsWithError) + 1
-		result := Analyze(cfg, mergeStats(statsWithError, statsNoError), nil)
-		ReportForGithubAction(buf, result)
-		assertGithubActionErrorsCount(t, buf.String(), totalErrorsCount)
-		assertContainStats(t, buf.String(), statsWithError)
-		assertNotContainStats(t, buf.String(), MakePackageStats(statsNoError))
-		assertNotContainStats(t, buf.String(), statsNoError)
+		assert.NoError(t, ReportForHTML(buf, Analyze(cfg, stats, nil)))
+
+		html := buf.String()
+		assert.Contains(t, html, "Total test coverage:</td><td>61.5% (8/13)")
+		assert.Contains(t, html, "File coverage threshold (60%) satisfied:</td><td class=\"fail\">FAIL")
+		assert.Contains(t, html, "Total coverage threshold (50%) satisfied:</td><td class=\"pass\">PASS")
+		assert.Contains(t, html, "<summary>org/pkg &mdash; 58.3% (7/12)</summary>")
+		assert.Contains(t, html, "org/pkg/foo.go</a> &mdash; 50.0% (5/10) <span class=\"fail\">(60% FAIL)</span>")
+		assert.Contains(t, html, "Uncovered lines: 4")
+		assert.Contains(t, html, `<tr class="ignored"><td class="num">1</td><td>package foo</td></tr>`)
+		assert.Contains(t, html, `<tr class="covered"><td class="num">3</td><td>func a() {}</td></tr>`)
+		assert.Contains(t, html, `<tr class="uncovered"><td class="num">4</td><td>func b() {}</td></tr>`)
+		assert.Contains(t, html, "source file is not available")
+		assert.NotContains(t, html, "Difference compared to the base")
 	})
 
-	t.Run("missing explanation annotations", func(t *testing.T) {
+	t.Run("with base", func(t *testing.T) {
 		t.Parallel()
 
+		base := []coverage.Stats{{Name: "org/pkg/foo.go", Total: 10, Covered: 6, UncoveredLines: []int{5}}}
+		cfg := Config{Diff: Diff{Threshold: ptr(0.0)}}
+
 		buf := &bytes.Buffer{}
-		result := AnalyzeResult{
-			FilesWithMissingExplanations: []coverage.Stats{
-				{
-					Name:                       "test.go",
-					AnnotationsWithoutComments: []int{10, 20},
-				},
-			},
-		}
-		ReportForGithubAction(buf, result)
+		assert.NoError(t, ReportForHTML(buf, Analyze(cfg, stats, base)))
 
-		output := buf.String()
-		assert.Contains(t, output, "file=test.go,title=Missing explanation for coverage-ignore,line=10")
-		assert.Contains(t, output, "file=test.go,title=Missing explanation for coverage-ignore,line=20")
-		assert.Contains(t, output, "add an explanation after the coverage-ignore annotation")
+		html := buf.String()
+		assert.Contains(t, html, "Difference compared to the base")
+		assert.Contains(t, html, "Coverage difference threshold (0.00%) satisfied:")
+		assert.Contains(t, html, "<td>org/pkg/foo.go</td><td>1</td><td>50.0% (5/10)</td><td>60.0% (6/10)</td><td>4</td><td>5</td>")
 	})
+}
 
-	t.Run("missing explanation annotations - empty list", func(t *testing.T) {
-		t.Parallel()
+func Test_ReportForCobertura(t *testing.T) {
+	t.Parallel()
 
-		buf := &bytes.Buffer{}
-		result := AnalyzeResult{
-			FilesWithMissingExplanations: []coverage.Stats{
-				{
-					Name:                       "test.go",
-					AnnotationsWithoutComments: []int{},
-				},
+	stats := []coverage.Stats{
+		{
+			Name: "org/pkg/foo.go", Path: "/src/org/pkg/foo.go", Total: 4, Covered: 3,
+			CoveredLines: []int{3, 4, 5}, UncoveredLines: []int{7},
+			Blocks: []coverage.Block{
+				{StartLine: 3, EndLine: 4, NumStmt: 2, Count: 5},
+				{StartLine: 5, EndLine: 5, NumStmt: 1, Count: 1},
+				{StartLine: 7, EndLine: 7, NumStmt: 1, Count: 0},
 			},
-		}
-		ReportForGithubAction(buf, result)
+			Functions: []coverage.FuncStats{
+				{Name: "Foo", StartLine: 2, EndLine: 6, Total: 3, Covered: 3},
+				{Name: "Bar", StartLine: 7, EndLine: 8, Total: 1, Covered: 0},
+			},
+		},
+		{Name: "org/other/bar.go", Total: 1, Covered: 1, CoveredLines: []int{1}},
+	}
 
-		output := buf.String()
-		assert.NotContains(t, output, "Missing explanation for coverage-ignore")
-	})
+	buf := &bytes.Buffer{}
+	assert.NoError(t, ReportForCobertura(buf, Analyze(Config{}, stats, nil)))
+	assert.True(t, strings.HasPrefix(buf.String(), xml.Header+"<!DOCTYPE coverage"))
+
+	type line struct {
+		Number int `xml:"number,attr"`
+		Hits   int `xml:"hits,attr"`
+	}
+
+	type class struct {
+		Filename string `xml:"filename,attr"`
+		LineRate string `xml:"line-rate,attr"`
+		Methods  []struct {
+			Name     string `xml:"name,attr"`
+			LineRate string `xml:"line-rate,attr"`
+		} `xml:"methods>method"`
+		Lines []line `xml:"lines>line"`
+	}
+
+	var report struct {
+		LineRate     string   `xml:"line-rate,attr"`
+		LinesCovered int      `xml:"lines-covered,attr"`
+		LinesValid   int      `xml:"lines-valid,attr"`
+		Sources      []string `xml:"sources>source"`
+		Packages     []struct {
+			Name     string  `xml:"name,attr"`
+			LineRate string  `xml:"line-rate,attr"`
+			Classes  []class `xml:"classes>class"`
+		} `xml:"packages>package"`
+	}
+
+	assert.NoError(t, xml.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, "0.8000", report.LineRate)
+	assert.Equal(t, 4, report.LinesCovered)
+	assert.Equal(t, 5, report.LinesValid)
+	assert.Equal(t, []string{filepath.FromSlash("/src")}, report.Sources)
+	assert.Len(t, report.Packages, 2)
+	assert.Equal(t, "org/other", report.Packages[0].Name)
+	assert.Equal(t, "org/pkg", report.Packages[1].Name)
+	assert.Equal(t, "0.7500", report.Packages[1].LineRate)
+
+	foo := report.Packages[1].Classes[0]
+	assert.Equal(t, "org/pkg/foo.go", foo.Filename)
+	assert.Equal(t, []line{{3, 5}, {4, 5}, {5, 1}, {7, 0}}, foo.Lines)
+	assert.Len(t, foo.Methods, 2)
+	assert.Equal(t, "1.0000", foo.Methods[0].LineRate)
+	assert.Equal(t, "0.0000", foo.Methods[1].LineRate)
+
+	// function without statements is fully covered
+	stats[0].Functions = append(stats[0].Functions,
+		coverage.FuncStats{Name: "Baz", StartLine: 9, EndLine: 9},
+	)
+	buf.Reset()
+	assert.NoError(t, ReportForCobertura(buf, Analyze(Config{}, stats, nil)))
+	report.Packages = nil
+	assert.NoError(t, xml.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, "1", report.Packages[1].Classes[0].Methods[2].LineRate)
 }
 
-func Test_ReportMissingExplanations(t *testing.T) {
+func Test_ReportForLcov(t *testing.T) {
 	t.Parallel()
 
-	t.Run("with missing explanations", func(t *testing.T) {
-		t.Parallel()
-
-		buf := &bytes.Buffer{}
-		result := AnalyzeResult{
-			FilesWithMissingExplanations: []coverage.Stats{
-				{
-					Name:                       "test.go",
-					AnnotationsWithoutComments: []int{10, 20},
-				},
+	stats := []coverage.Stats{
+		{
+			Name: "org/pkg/foo.go", Path: "/src/org/pkg/foo.go", Total: 4, Covered: 3,
+			CoveredLines: []int{3, 4, 5}, UncoveredLines: []int{7},
+			Blocks: []coverage.Block{
+				{StartLine: 3, EndLine: 4, NumStmt: 2, Count: 5},
+				{StartLine: 5, EndLine: 5, NumStmt: 1, Count: 1},
+				{StartLine: 7, EndLine: 7, NumStmt: 1, Count: 0},
 			},
-		}
-
-		ReportForHuman(buf, result)
+			Functions: []coverage.FuncStats{
+				{Name: "Foo", StartLine: 2, EndLine: 6, Total: 3, Covered: 3},
+				{Name: "Bar", StartLine: 7, EndLine: 8, Total: 1, Covered: 0},
+			},
+		},
+		{Name: "org/other/bar.go", Total: 1, Covered: 1, CoveredLines: []int{1}},
+	}
 
-		output := buf.String()
-		assert.Contains(t, output, "Files with missing explanation for coverage-ignore annotation:")
-		assert.Contains(t, output, "test.go")
-		assert.Contains(t, output, "10, 20")
-	})
+	buf := &bytes.Buffer{}
+	assert.NoError(t, ReportForLcov(buf, Analyze(Config{}, stats, nil)))
+	assert.Equal(t, `TN:
+SF:org/other/bar.go
+FNF:0
+FNH:0
+DA:1,1
+LF:1
+LH:1
+end_of_record
+TN:
+SF:/src/org/pkg/foo.go
+FN:2,Foo
+FN:7,Bar
+FNDA:5,Foo
+FNDA:0,Bar
+FNF:2
+FNH:1
+DA:3,5
+DA:4,5
+DA:5,1
+DA:7,0
+LF:4
+LH:3
+end_of_record
+`, buf.String())
+
+	// failing writer
+	assert.Error(t, ReportForLcov(errWriter{}, Analyze(Config{}, stats, nil)))
+}
 
-	t.Run("with no missing explanations", func(t *testing.T) {
-		t.Parallel()
+func Test_ReportForJSON(t *testing.T) {
+	t.Parallel()
 
-		buf := &bytes.Buffer{}
-		result := AnalyzeResult{
-			FilesWithMissingExplanations: []coverage.Stats{},
-		}
+	baseStats := []coverage.Stats{
+		{Name: "org/pkg/foo.go", Total: 4, Covered: 4},
+	}
+	stats := []coverage.Stats{
+		{
+			Name: "org/pkg/foo.go", Total: 4, Covered: 2,
+			CoveredLines: []int{1, 2}, UncoveredLines: []int{3, 4, 7},
+		},
+		{
+			Name: "org/other/bar.go", Total: 1, Covered: 1,
+			CoveredLines: []int{1}, AnnotationsWithoutComments: []int{5},
+		},
+	}
+	cfg := Config{
+		Threshold:              Threshold{File: 50, Total: 90},
+		Override:               []Override{{Path: "^org/pkg/foo.go$", Threshold: ptr(60.0)}},
+		ForceAnnotationComment: true,
+	}
+	result := Analyze(cfg, stats, baseStats)
+
+	type reportStats struct {
+		Name           string `json:"name"`
+		Threshold      int    `json:"threshold"`
+		Pass           bool   `json:"pass"`
+		UncoveredLines []struct {
+			Start int `json:"start"`
+			End   int `json:"end"`
+		} `json:"uncovered-lines"`
+	}
 
-		ReportForHuman(buf, result)
+	var report struct {
+		Version int  `json:"version"`
+		Pass    bool `json:"pass"`
+		Checks  []struct {
+			Name    string `json:"name"`
+			Enabled bool   `json:"enabled"`
+			Pass    bool   `json:"pass"`
+		} `json:"checks"`
+		Total reportStats   `json:"total"`
+		Files []reportStats `json:"files"`
+		Diff  struct {
+			Percentage float64 `json:"percentage"`
+			Files      []struct {
+				Name string `json:"name"`
+			} `json:"files"`
+		} `json:"diff"`
+		Missing []struct {
+			Name  string `json:"name"`
+			Lines []int  `json:"lines"`
+		} `json:"missing-explanations"`
+	}
 
-		output := buf.String()
-		assert.NotContains(t, output, "Files with missing explanations for coverage-ignore annotations:")
-	})
+	buf := &bytes.Buffer{}
+	assert.NoError(t, ReportForJSON(buf, result))
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+
+	assert.Equal(t, JSONReportVersion, report.Version)
+	assert.False(t, report.Pass)
+	assert.Len(t, report.Checks, 13)
+	assert.Equal(t, "file", report.Checks[0].Name)
+	assert.True(t, report.Checks[0].Enabled)
+	assert.False(t, report.Checks[0].Pass)
+	assert.Equal(t, "explanations", report.Checks[6].Name)
+	assert.True(t, report.Checks[6].Enabled)
+	assert.False(t, report.Checks[6].Pass)
+	assert.Equal(t, "issues", report.Checks[7].Name)
+	assert.False(t, report.Checks[7].Enabled)
+	assert.True(t, report.Checks[7].Pass)
+	assert.Equal(t, "max-ignored", report.Checks[8].Name)
+	assert.False(t, report.Checks[8].Enabled)
+	assert.Equal(t, "ratchet", report.Checks[9].Name)
+	assert.False(t, report.Checks[9].Enabled)
+	assert.Equal(t, "unmatched-annotations", report.Checks[10].Name)
+	assert.True(t, report.Checks[10].Enabled)
+	assert.True(t, report.Checks[10].Pass)
+	assert.Equal(t, "expired-annotations", report.Checks[11].Name)
+	assert.True(t, report.Checks[11].Enabled)
+	assert.True(t, report.Checks[11].Pass)
+	assert.Equal(t, "invalid-annotations", report.Checks[12].Name)
+	assert.True(t, report.Checks[12].Enabled)
+	assert.True(t, report.Checks[12].Pass)
+
+	assert.Equal(t, 90, report.Total.Threshold)
+	assert.False(t, report.Total.Pass)
+
+	assert.Len(t, report.Files, 2)
+	assert.Equal(t, "org/other/bar.go", report.Files[0].Name)
+	assert.Equal(t, 50, report.Files[0].Threshold)
+	assert.True(t, report.Files[0].Pass)
+	assert.Equal(t, "org/pkg/foo.go", report.Files[1].Name)
+	assert.Equal(t, 60, report.Files[1].Threshold)
+	assert.False(t, report.Files[1].Pass)
+	assert.Len(t, report.Files[1].UncoveredLines, 2)
+	assert.Equal(t, 3, report.Files[1].UncoveredLines[0].Start)
+	assert.Equal(t, 4, report.Files[1].UncoveredLines[0].End)
+	assert.Equal(t, 7, report.Files[1].UncoveredLines[1].Start)
+
+	assert.Len(t, report.Diff.Files, 1)
+	assert.Equal(t, "org/pkg/foo.go", report.Diff.Files[0].Name)
+
+	assert.Len(t, report.Missing, 1)
+	assert.Equal(t, []int{5}, report.Missing[0].Lines)
+
+	// MarshalJSON encodes same report, for values and pointers
+	data, err := json.Marshal(result)
+	assert.NoError(t, err)
+	assert.JSONEq(t, buf.String(), string(data))
+
+	var res Result = result
+
+	data, err = json.Marshal(&res)
+	assert.NoError(t, err)
+	assert.JSONEq(t, buf.String(), string(data))
+
+	// report decodes back into schema without unknown fields
+	var decoded JSONReport
+
+	dec := json.NewDecoder(bytes.NewReader(buf.Bytes()))
+	dec.DisallowUnknownFields()
+	assert.NoError(t, dec.Decode(&decoded))
+	assert.Equal(t, MakeJSONReport(result), decoded)
+
+	// percentage is not rounded
+	data, err = json.Marshal(AnalyzeResult{TotalStats: coverage.Stats{Total: 3, Covered: 2}})
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.InDelta(t, 200.0/3, decoded.Total.Percentage, 0)
+
+	// failing writer
+	assert.Error(t, ReportForJSON(errWriter{}, result))
 }
 
-//nolint:paralleltest // must not be parallel because it uses env
-func Test_SetGithubActionOutput(t *testing.T) {
-	if testing.Short() {
-		return
+func Test_ReportForSarif(t *testing.T) {
+	t.Parallel()
+
+	type sarifResult struct {
+		RuleID    string `json:"ruleId"`
+		Level     string `json:"level"`
+		Locations []struct {
+			PhysicalLocation struct {
+				ArtifactLocation struct {
+					URI string `json:"uri"`
+				} `json:"artifactLocation"`
+				Region *struct {
+					StartLine int `json:"startLine"`
+					EndLine   int `json:"endLine"`
+				} `json:"region"`
+			} `json:"physicalLocation"`
+		} `json:"locations"`
 	}
 
-	t.Run("writing value to output with error", func(t *testing.T) {
-		err := SetOutputValue(errWriter{}, "key", "val")
-		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
-		assert.Contains(t, err.Error(), "key")
-	})
+	type sarifLog struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []sarifResult `json:"results"`
+		} `json:"runs"`
+	}
 
-	t.Run("no env file", func(t *testing.T) {
-		t.Setenv(GaOutputFileEnv, "")
+	decode := func(t *testing.T, result AnalyzeResult, withUncoveredLines bool) []sarifResult {
+		t.Helper()
 
-		err := SetGithubActionOutput(AnalyzeResult{}, "")
-		assert.Error(t, err)
-	})
+		buf := &bytes.Buffer{}
+		assert.NoError(t, ReportForSarif(buf, result, withUncoveredLines))
 
-	t.Run("ok", func(t *testing.T) {
-		testFile := t.TempDir() + "/ga.output"
+		var log sarifLog
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+		assert.Equal(t, "2.1.0", log.Version)
+		assert.Len(t, log.Runs, 1)
+		assert.Equal(t, "go-test-coverage", log.Runs[0].Tool.Driver.Name)
+		assert.NotEmpty(t, log.Runs[0].Tool.Driver.Rules)
 
-		t.Setenv(GaOutputFileEnv, testFile)
+		return log.Runs[0].Results
+	}
 
-		err := SetGithubActionOutput(AnalyzeResult{}, "")
-		assert.NoError(t, err)
+	t.Run("no issues", func(t *testing.T) {
+		t.Parallel()
 
-		contentBytes, err := os.ReadFile(testFile)
-		assert.NoError(t, err)
+		stats := randStats(prefix, 100, 100)
+		results := decode(t, Analyze(Config{}, stats, nil), false)
+		assert.Empty(t, results)
+	})
 
-		content := string(contentBytes)
-		assert.Equal(t, 1, strings.Count(content, GaOutputTotalCoverage))
+	t.Run("issues", func(t *testing.T) {
+		t.Parallel()
+
+		stats := []coverage.Stats{
+			{
+				Name: "org/pkg/foo.go", Total: 4, Covered: 2,
+				UncoveredLines: []int{3, 4, 7}, AnnotationsWithoutComments: []int{9},
+			},
+			{Name: "org/pkg/bar.go", Total: 1, Covered: 1},
+		}
+		cfg := Config{
+			Threshold:              Threshold{File: 60, Package: 90, Total: 90},
+			ForceAnnotationComment: true,
+		}
+		results := decode(t, Analyze(cfg, stats, nil), false)
+		assert.Len(t, results, 4)
+
+		assert.Equal(t, "file-coverage-below-threshold", results[0].RuleID)
+		assert.Equal(t, "error", results[0].Level)
+		assert.Equal(t, "org/pkg/foo.go", results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		assert.Equal(t, 1, results[0].Locations[0].PhysicalLocation.Region.StartLine)
+
+		assert.Equal(t, "package-coverage-below-threshold", results[1].RuleID)
+		assert.Equal(t, "org/pkg/bar.go", results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		assert.Nil(t, results[1].Locations[0].PhysicalLocation.Region)
+
+		assert.Equal(t, "total-coverage-below-threshold", results[2].RuleID)
+		assert.Equal(t, "go.mod", results[2].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		assert.Nil(t, results[2].Locations[0].PhysicalLocation.Region)
+
+		assert.Equal(t, "missing-coverage-ignore-explanation", results[3].RuleID)
+		assert.Equal(t, 9, results[3].Locations[0].PhysicalLocation.Region.StartLine)
+	})
+
+	t.Run("functions", func(t *testing.T) {
+		t.Parallel()
+
+		result := AnalyzeResult{
+			FunctionsBelowThreshold: coverage.StatsFunctions([]coverage.Stats{{
+				Name: "org/pkg/foo.go",
+				Functions: []coverage.FuncStats{
+					{Name: "Foo", StartLine: 3, EndLine: 8, Total: 4, Covered: 2},
+				},
+			}}),
+		}
+		results := decode(t, result, false)
+		assert.Len(t, results, 1)
+		assert.Equal(t, "function-coverage-below-threshold", results[0].RuleID)
+		assert.Equal(t, "org/pkg/foo.go", results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		assert.Equal(t, 3, results[0].Locations[0].PhysicalLocation.Region.StartLine)
+
+		// function without known position is reported on first line of file
+		result.FunctionsBelowThreshold = []coverage.Stats{{Name: "org/pkg/foo.go:Foo"}}
+		results = decode(t, result, false)
+		assert.Len(t, results, 1)
+		assert.Equal(t, "org/pkg/foo.go", results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		assert.Equal(t, 1, results[0].Locations[0].PhysicalLocation.Region.StartLine)
+	})
+
+	t.Run("annotations", func(t *testing.T) {
+		t.Parallel()
+
+		result := AnalyzeResult{
+			FilesWithUnmatchedAnnotations: []coverage.Stats{
+				{Name: "org/pkg/foo.go", UnmatchedAnnotations: []int{10, 20}},
+			},
+			FilesWithExpiredAnnotations: []coverage.Stats{
+				{Name: "org/pkg/foo.go", ExpiredAnnotations: []int{30}},
+			},
+			FilesWithMissingIssues: []coverage.Stats{
+				{Name: "org/pkg/bar.go", AnnotationsWithoutIssue: []int{40}},
+			},
+		}
+		results := decode(t, result, false)
+		assert.Len(t, results, 4)
+		assert.Equal(t, "unmatched-coverage-ignore-annotation", results[0].RuleID)
+		assert.Equal(t, "org/pkg/foo.go", results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		assert.Equal(t, 10, results[0].Locations[0].PhysicalLocation.Region.StartLine)
+		assert.Equal(t, 20, results[1].Locations[0].PhysicalLocation.Region.StartLine)
+		assert.Equal(t, "expired-coverage-ignore-annotation", results[2].RuleID)
+		assert.Equal(t, 30, results[2].Locations[0].PhysicalLocation.Region.StartLine)
+		assert.Equal(t, "missing-coverage-ignore-issue", results[3].RuleID)
+		assert.Equal(t, "org/pkg/bar.go", results[3].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		assert.Equal(t, 40, results[3].Locations[0].PhysicalLocation.Region.StartLine)
+	})
+
+	t.Run("uncovered lines", func(t *testing.T) {
+		t.Parallel()
+
+		stats := []coverage.Stats{
+			{Name: "org/pkg/foo.go", Total: 4, Covered: 2, UncoveredLines: []int{3, 4, 7}},
+		}
+		result := Analyze(Config{}, stats, nil)
+
+		assert.Empty(t, decode(t, result, false))
+
+		results := decode(t, result, true)
+		assert.Len(t, results, 2)
+		assert.Equal(t, "uncovered-lines", results[0].RuleID)
+		assert.Equal(t, "note", results[0].Level)
+		assert.Equal(t, 3, results[0].Locations[0].PhysicalLocation.Region.StartLine)
+		assert.Equal(t, 4, results[0].Locations[0].PhysicalLocation.Region.EndLine)
+		assert.Equal(t, 7, results[1].Locations[0].PhysicalLocation.Region.StartLine)
+	})
+
+	t.Run("changed lines not covered", func(t *testing.T) {
+		t.Parallel()
+
+		result := AnalyzeResult{
+			Threshold:  Threshold{NewCode: 100},
+			HasNewCode: true,
+			NewCode: []coverage.Stats{
+				{Name: "org/pkg/foo.go", Total: 3, Covered: 1, UncoveredLines: []int{5, 6}},
+			},
+		}
+		results := decode(t, result, false)
+		assert.Len(t, results, 1)
+		assert.Equal(t, "changed-lines-not-covered", results[0].RuleID)
+		assert.Equal(t, 5, results[0].Locations[0].PhysicalLocation.Region.StartLine)
+		assert.Equal(t, 6, results[0].Locations[0].PhysicalLocation.Region.EndLine)
+	})
+
+	t.Run("result is not modified", func(t *testing.T) {
+		t.Parallel()
+
+		stats := randStats(prefix, 0, 50)
+		result := Analyze(Config{Threshold: Threshold{File: 60, Package: 60}}, stats, nil)
+		files := copyStats(result.FilesBelowThreshold)
+		packages := copyStats(result.PackagesBelowThreshold)
+
+		decode(t, result, true)
+		assert.Equal(t, files, result.FilesBelowThreshold)
+		assert.Equal(t, packages, result.PackagesBelowThreshold)
+	})
+
+	t.Run("failing writer", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Error(t, ReportForSarif(errWriter{}, AnalyzeResult{}, false))
+	})
+}
+
+func Test_ReportForJUnit(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		Name      string `xml:"name,attr"`
+		ClassName string `xml:"classname,attr"`
+		Failure   *struct {
+			Message string `xml:"message,attr"`
+			Text    string `xml:",chardata"`
+		} `xml:"failure"`
+	}
+
+	type testSuites struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Suites   []struct {
+			Name      string     `xml:"name,attr"`
+			Tests     int        `xml:"tests,attr"`
+			Failures  int        `xml:"failures,attr"`
+			TestCases []testCase `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+
+	decode := func(t *testing.T, result AnalyzeResult) testSuites {
+		t.Helper()
+
+		buf := &bytes.Buffer{}
+		assert.NoError(t, ReportForJUnit(buf, result))
+		assert.True(t, strings.HasPrefix(buf.String(), xml.Header))
+
+		var ts testSuites
+		assert.NoError(t, xml.Unmarshal(buf.Bytes(), &ts))
+
+		return ts
+	}
+
+	t.Run("no checks enabled", func(t *testing.T) {
+		t.Parallel()
+
+		ts := decode(t, Analyze(Config{}, randStats(prefix, 10, 100), nil))
+		assert.Equal(t, 0, ts.Tests)
+		assert.Empty(t, ts.Suites)
+	})
+
+	t.Run("all checks", func(t *testing.T) {
+		t.Parallel()
+
+		diffThreshold := 0.0
+		stats := []coverage.Stats{
+			{
+				Name: "org/pkg/foo.go", Total: 4, Covered: 2, UncoveredLines: []int{3, 4},
+				AnnotationsWithoutComments: []int{9},
+				Functions: []coverage.FuncStats{
+					{Name: "Foo", Total: 4, Covered: 2},
+				},
+			},
+			{
+				Name: "org/pkg/bar.go", Total: 1, Covered: 1,
+				Functions: []coverage.FuncStats{
+					{Name: "Bar", Total: 1, Covered: 1},
+				},
+			},
+		}
+		base := []coverage.Stats{{Name: "org/pkg/foo.go", Total: 4, Covered: 4}}
+		cfg := Config{
+			Threshold:              Threshold{File: 60, Package: 50, Function: 60, Total: 90},
+			Diff:                   Diff{Threshold: &diffThreshold},
+			ForceAnnotationComment: true,
+		}
+
+		ts := decode(t, Analyze(cfg, stats, base))
+		assert.Equal(t, 9, ts.Tests)
+		assert.Equal(t, 5, ts.Failures)
+		assert.Len(t, ts.Suites, 6)
+
+		file := ts.Suites[0]
+		assert.Equal(t, "coverage.file", file.Name)
+		assert.Equal(t, 2, file.Tests)
+		assert.Equal(t, 1, file.Failures)
+		assert.Equal(t, "org/pkg/bar.go", file.TestCases[0].Name)
+		assert.Nil(t, file.TestCases[0].Failure)
+		assert.Equal(t, "org/pkg/foo.go", file.TestCases[1].Name)
+		assert.Equal(t, "coverage: 50.0% (2/4); threshold: 60%", file.TestCases[1].Failure.Message)
+		assert.Equal(t, "uncovered lines: 3-4", file.TestCases[1].Failure.Text)
+
+		assert.Equal(t, "coverage.package", ts.Suites[1].Name)
+		assert.Equal(t, 0, ts.Suites[1].Failures)
+
+		// all functions are listed, same as files and packages
+		assert.Equal(t, "coverage.function", ts.Suites[2].Name)
+		assert.Equal(t, 2, ts.Suites[2].Tests)
+		assert.Equal(t, 1, ts.Suites[2].Failures)
+		assert.Equal(t, "org/pkg/bar.go:Bar", ts.Suites[2].TestCases[0].Name)
+		assert.Nil(t, ts.Suites[2].TestCases[0].Failure)
+		assert.Equal(t, "org/pkg/foo.go:Foo", ts.Suites[2].TestCases[1].Name)
+		assert.NotNil(t, ts.Suites[2].TestCases[1].Failure)
+
+		assert.Equal(t, "coverage.total", ts.Suites[3].Name)
+		assert.Equal(t, 1, ts.Suites[3].Failures)
+
+		assert.Equal(t, "coverage.diff", ts.Suites[4].Name)
+		assert.Equal(t, 1, ts.Suites[4].Failures)
+
+		assert.Equal(t, "coverage.explanations", ts.Suites[5].Name)
+		assert.Equal(t, 2, ts.Suites[5].Tests)
+		assert.Equal(t, 1, ts.Suites[5].Failures)
+		assert.Contains(t, ts.Suites[5].TestCases[1].Failure.Text, "lines: 9")
+	})
+
+	t.Run("function and new code checks", func(t *testing.T) {
+		t.Parallel()
+
+		result := AnalyzeResult{
+			Threshold:  Threshold{Function: 50, NewCode: 100},
+			HasNewCode: true,
+			NewCode: []coverage.Stats{
+				{Name: "org/pkg/foo.go", Total: 3, Covered: 1, UncoveredLines: []int{5, 6}},
+			},
+			Functions: []coverage.Stats{
+				{Name: "org/pkg/foo.go:Foo", Total: 2, Covered: 1, Threshold: 50},
+			},
+		}
+
+		ts := decode(t, result)
+		assert.Len(t, ts.Suites, 2)
+		assert.Equal(t, "org/pkg/foo.go:Foo", ts.Suites[0].TestCases[0].Name)
+		assert.Nil(t, ts.Suites[0].TestCases[0].Failure)
+		assert.Equal(t, "coverage.new-code", ts.Suites[1].Name)
+		assert.Equal(t, "org/pkg/foo.go: 5-6\n", ts.Suites[1].TestCases[0].Failure.Text)
+	})
+
+	t.Run("failing writer", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Error(t, ReportForJUnit(errWriter{}, AnalyzeResult{}))
+	})
+}
+
+func Test_ReportForGithubAction(t *testing.T) {
+	t.Parallel()
+
+	const prefix = "organization.org/pkg/"
+
+	t.Run("total coverage - pass", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{Threshold: Threshold{Total: 100}}
+		statsNoError := randStats(prefix, 100, 100)
+		result := Analyze(cfg, statsNoError, nil)
+		ReportForGithubAction(buf, result)
+		assertGithubActionErrorsCount(t, buf.String(), 0)
+		assertNotContainStats(t, buf.String(), statsNoError)
+	})
+
+	t.Run("total coverage - fail", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		statsWithError := randStats(prefix, 0, 9)
+		statsNoError := randStats(prefix, 10, 100)
+		cfg := Config{Threshold: Threshold{Total: 10}}
+		result := Analyze(cfg, mergeStats(statsWithError, statsNoError), nil)
+		ReportForGithubAction(buf, result)
+		assertGithubActionErrorsCount(t, buf.String(), 1)
+		assertNotContainStats(t, buf.String(), statsWithError)
+		assertNotContainStats(t, buf.String(), statsNoError)
+	})
+
+	t.Run("file coverage - pass", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{Threshold: Threshold{File: 10}}
+		statsNoError := randStats(prefix, 10, 100)
+		result := Analyze(cfg, statsNoError, nil)
+		ReportForGithubAction(buf, result)
+		assertGithubActionErrorsCount(t, buf.String(), 0)
+		assertNotContainStats(t, buf.String(), statsNoError)
+	})
+
+	t.Run("file coverage - fail", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{Threshold: Threshold{File: 10}}
+		statsWithError := randStats(prefix, 0, 9)
+		statsNoError := randStats(prefix, 10, 100)
+		result := Analyze(cfg, mergeStats(statsWithError, statsNoError), nil)
+		ReportForGithubAction(buf, result)
+		assertGithubActionErrorsCount(t, buf.String(), len(statsWithError))
+		assertContainStats(t, buf.String(), statsWithError)
+		assertNotContainStats(t, buf.String(), statsNoError)
+	})
+
+	t.Run("package coverage - pass", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{Threshold: Threshold{Package: 10}}
+		statsNoError := randStats(prefix, 10, 100)
+		result := Analyze(cfg, statsNoError, nil)
+		ReportForGithubAction(buf, result)
+		assertGithubActionErrorsCount(t, buf.String(), 0)
+		assertNotContainStats(t, buf.String(), MakePackageStats(statsNoError))
+		assertNotContainStats(t, buf.String(), statsNoError)
+	})
+
+	t.Run("package coverage - fail", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{Threshold: Threshold{Package: 10}}
+		statsWithError := randStats(prefix, 0, 9)
+		statsNoError := randStats(prefix, 10, 100)
+		result := Analyze(cfg, mergeStats(statsWithError, statsNoError), nil)
+		ReportForGithubAction(buf, result)
+		assertGithubActionErrorsCount(t, buf.String(), len(MakePackageStats(statsWithError)))
+		assertContainStats(t, buf.String(), MakePackageStats(statsWithError))
+		assertNotContainStats(t, buf.String(), MakePackageStats(statsNoError))
+		assertNotContainStats(t, buf.String(), statsWithError)
+		assertNotContainStats(t, buf.String(), statsNoError)
+	})
+
+	t.Run("file, package and total - fail", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{Threshold: Threshold{File: 10, Package: 10, Total: 100}}
+		statsWithError := randStats(prefix, 0, 9)
+		statsNoError := randStats(prefix, 10, 100)
+		totalErrorsCount := len(MakePackageStats(statsWithError)) + len(statsWithError) + 1
+		result := Analyze(cfg, mergeStats(statsWithError, statsNoError), nil)
+		ReportForGithubAction(buf, result)
+		assertGithubActionErrorsCount(t, buf.String(), totalErrorsCount)
+		assertContainStats(t, buf.String(), statsWithError)
+		assertNotContainStats(t, buf.String(), MakePackageStats(statsNoError))
+		assertNotContainStats(t, buf.String(), statsNoError)
+	})
+
+	t.Run("function coverage - fail", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		stats := []coverage.Stats{{
+			Name: "org/pkg/foo.go", Total: 6, Covered: 4,
+			Functions: []coverage.FuncStats{
+				{Name: "Foo", StartLine: 3, EndLine: 8, Total: 4, Covered: 2},
+				{Name: "(*T).Bar", StartLine: 10, EndLine: 12, Total: 2, Covered: 2},
+			},
+		}}
+		ReportForGithubAction(buf, Analyze(Config{Threshold: Threshold{Function: 80}}, stats, nil))
+		assertGithubActionErrorsCount(t, buf.String(), 1)
+		assert.Contains(t, buf.String(),
+			"::error file=org/pkg/foo.go,title=Function test coverage below threshold,line=3::"+
+				"Function test coverage below threshold: function: Foo; coverage: 50.0% (2/4); threshold: 80%")
+	})
+
+	t.Run("new code - fail", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		result := AnalyzeResult{
+			Threshold:  Threshold{NewCode: 100},
+			HasNewCode: true,
+			NewCode: []coverage.Stats{
+				{Name: "foo.go", Total: 4, Covered: 4},
+				{Name: "bar.go", Total: 5, Covered: 2, UncoveredLines: []int{3, 4, 10}},
+			},
+		}
+		ReportForGithubAction(buf, result)
+		assertGithubActionErrorsCount(t, buf.String(), 2)
+		assert.Contains(t, buf.String(), "file=bar.go,title=Changed lines not covered by tests,line=3,endLine=4")
+		assert.Contains(t, buf.String(), "file=bar.go,title=Changed lines not covered by tests,line=10,endLine=10")
+	})
+
+	t.Run("missing explanation annotations", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		result := AnalyzeResult{
+			FilesWithMissingExplanations: []coverage.Stats{
+				{
+					Name:                       "test.go",
+					AnnotationsWithoutComments: []int{10, 20},
+				},
+			},
+		}
+		ReportForGithubAction(buf, result)
+
+		output := buf.String()
+		assert.Contains(t, output, "file=test.go,title=Missing explanation for coverage-ignore,line=10")
+		assert.Contains(t, output, "file=test.go,title=Missing explanation for coverage-ignore,line=20")
+		assert.Contains(t, output, "add an explanation after the coverage-ignore annotation")
+	})
+
+	t.Run("missing explanation annotations - empty list", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		result := AnalyzeResult{
+			FilesWithMissingExplanations: []coverage.Stats{
+				{
+					Name:                       "test.go",
+					AnnotationsWithoutComments: []int{},
+				},
+			},
+		}
+		ReportForGithubAction(buf, result)
+
+		output := buf.String()
+		assert.NotContains(t, output, "Missing explanation for coverage-ignore")
+	})
+}
+
+func Test_ReportMissingExplanations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("with missing explanations", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		result := AnalyzeResult{
+			FilesWithMissingExplanations: []coverage.Stats{
+				{
+					Name:                       "test.go",
+					AnnotationsWithoutComments: []int{10, 20},
+				},
+			},
+		}
+
+		ReportForHuman(buf, result)
+
+		output := buf.String()
+		assert.Contains(t, output, "Files with missing explanation for coverage-ignore annotation:")
+		assert.Contains(t, output, "test.go")
+		assert.Contains(t, output, "10, 20")
+	})
+
+	t.Run("with no missing explanations", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		result := AnalyzeResult{
+			FilesWithMissingExplanations: []coverage.Stats{},
+		}
+
+		ReportForHuman(buf, result)
+
+		output := buf.String()
+		assert.NotContains(t, output, "Files with missing explanations for coverage-ignore annotations:")
+	})
+}
+
+func Test_ReportUnmatchedAnnotations(t *testing.T) {
+	t.Parallel()
+
+	result := AnalyzeResult{
+		FilesWithUnmatchedAnnotations: []coverage.Stats{
+			{Name: "test.go", UnmatchedAnnotations: []int{10, 20}},
+		},
+	}
+	assert.False(t, result.Pass())
+
+	buf := &bytes.Buffer{}
+	ReportForHuman(buf, result)
+	assert.Contains(t, buf.String(),
+		"Files with unmatched coverage-ignore-start/end annotation or misplaced directive:")
+	assert.Contains(t, buf.String(), "test.go\t10, 20")
+
+	buf = &bytes.Buffer{}
+	ReportForGithubAction(buf, result)
+	assert.Contains(t, buf.String(),
+		"::error file=test.go,title=Unmatched coverage-ignore range annotation,line=10::")
+	assert.Contains(t, buf.String(),
+		"::error file=test.go,title=Unmatched coverage-ignore range annotation,line=20::")
+
+	buf = &bytes.Buffer{}
+	assert.NoError(t, ReportForJSON(buf, result))
+	assert.Contains(t, buf.String(), `"unmatched-annotations"`)
+
+	data, err := json.Marshal(result)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `{"name":"unmatched-annotations","enabled":true,"pass":false}`)
+
+	buf = &bytes.Buffer{}
+	ReportForHuman(buf, AnalyzeResult{})
+	assert.NotContains(t, buf.String(), "unmatched")
+}
+
+func Test_ReportAnnotationMeta(t *testing.T) {
+	t.Parallel()
+
+	result := AnalyzeResult{
+		FilesWithExpiredAnnotations: []coverage.Stats{
+			{Name: "test.go", ExpiredAnnotations: []int{10, 20}},
+		},
+		FilesWithInvalidAnnotations: []coverage.Stats{
+			{Name: "test.go", InvalidAnnotations: []int{25}},
+		},
+		FilesWithMissingIssues: []coverage.Stats{
+			{Name: "test.go", AnnotationsWithoutIssue: []int{30}},
+		},
+	}
+	assert.False(t, result.Pass())
+
+	invalidOnly := AnalyzeResult{FilesWithInvalidAnnotations: result.FilesWithInvalidAnnotations}
+	assert.False(t, invalidOnly.Pass())
+
+	buf := &bytes.Buffer{}
+	ReportForHuman(buf, result)
+	assert.Contains(t, buf.String(), "Files with expired coverage-ignore annotation:")
+	assert.Contains(t, buf.String(), "test.go\t10, 20")
+	assert.Contains(t, buf.String(),
+		"Files with invalid until date of coverage-ignore annotation:")
+	assert.Contains(t, buf.String(), "test.go\t25")
+	assert.Contains(t, buf.String(), "Files with missing issue reference for coverage-ignore annotation:")
+	assert.Contains(t, buf.String(), "test.go\t30")
+
+	buf = &bytes.Buffer{}
+	ReportForGithubAction(buf, result)
+	assert.Contains(t, buf.String(),
+		"::error file=test.go,title=Expired coverage-ignore annotation,line=10::")
+	assert.Contains(t, buf.String(),
+		"::error file=test.go,title=Expired coverage-ignore annotation,line=20::")
+	assert.Contains(t, buf.String(),
+		"::error file=test.go,title=Invalid coverage-ignore annotation,line=25::")
+	assert.Contains(t, buf.String(),
+		"::error file=test.go,title=Missing issue reference for coverage-ignore,line=30::")
+
+	buf = &bytes.Buffer{}
+	assert.NoError(t, ReportForJSON(buf, result))
+	assert.Contains(t, buf.String(), `"expired-annotations"`)
+	assert.Contains(t, buf.String(), `"invalid-annotations"`)
+	assert.Contains(t, buf.String(), `"missing-issues"`)
+
+	data, err := json.Marshal(result)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `{"name":"expired-annotations","enabled":true,"pass":false}`)
+	assert.Contains(t, string(data), `{"name":"invalid-annotations","enabled":true,"pass":false}`)
+
+	buf = &bytes.Buffer{}
+	ReportForHuman(buf, AnalyzeResult{})
+	assert.NotContains(t, buf.String(), "expired")
+	assert.NotContains(t, buf.String(), "invalid")
+	assert.NotContains(t, buf.String(), "issue reference")
+}
+
+func Test_ReportIgnoredStatements(t *testing.T) {
+	t.Parallel()
+
+	stats := []coverage.Stats{
+		{Name: "org/a/foo.go", Total: 8, Covered: 8, Ignored: 2, IgnoredByAnnotation: []coverage.AnnotationStats{
+			{Line: 10, Ignored: 1},
+			{Line: 20, Ignored: 1},
+		}},
+		{Name: "org/a/bar.go", Total: 10, Covered: 10},
+		{Name: "org/b/baz.go", Total: 5, Covered: 5, Ignored: 5, IgnoredByAnnotation: []coverage.AnnotationStats{
+			{Line: 3, Ignored: 5},
+		}},
+	}
+
+	// tabs used for alignment of columns are collapsed
+	humanReport := func(result AnalyzeResult) string {
+		buf := &bytes.Buffer{}
+		ReportForHuman(buf, result)
+
+		return regexp.MustCompile("\t+").ReplaceAllString(buf.String(), "\t")
+	}
+
+	// ignored statements are reported without limits
+	result := Analyze(Config{}, stats, nil)
+	assert.True(t, result.Pass())
+
+	out := humanReport(result)
+	assert.Contains(t, out, "Statements ignored with coverage-ignore annotations:")
+	assert.Contains(t, out, "org/a/foo.go\t2 (20%)\t10 (1), 20 (1)")
+	assert.Contains(t, out, "org/b/baz.go\t5 (50%)\t3 (5)")
+	assert.Contains(t, out, "total\t7 (23.3%)")
+	assert.NotContains(t, out, "org/a/bar.go")
+	assert.NotContains(t, out, "ignored statements limit")
+
+	// limits are exceeded
+	result = Analyze(Config{MaxIgnored: MaxIgnored{File: 30, Package: 40, Total: 20}}, stats, nil)
+	assert.False(t, result.Pass())
+	assert.True(t, result.PassCoverage())
+	assert.Len(t, result.FilesAboveMaxIgnored, 1)
+	assert.Len(t, result.PackagesAboveMaxIgnored, 1)
+	assert.False(t, result.MeetsMaxIgnoredTotal())
+
+	out = humanReport(result)
+	assert.Contains(t, out, "File ignored statements limit (30%) satisfied:\tFAIL")
+	assert.Contains(t, out, "org/b/baz.go\t5 (50%)\t30%")
+	assert.Contains(t, out, "Package ignored statements limit (40%) satisfied:\tFAIL")
+	assert.Contains(t, out, "org/b\t5 (50%)\t40%")
+	assert.Contains(t, out, "Total ignored statements limit (20%) satisfied:\tFAIL")
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, ReportForJSON(buf, result))
+	assert.Contains(t, buf.String(), `"ignored": 7`)
+
+	// limits are satisfied
+	result = Analyze(Config{MaxIgnored: MaxIgnored{File: 50, Package: 50, Total: 30}}, stats, nil)
+	assert.True(t, result.Pass())
+
+	out = humanReport(result)
+	assert.Contains(t, out, "File ignored statements limit (50%) satisfied:\tPASS")
+	assert.Contains(t, out, "Total ignored statements limit (30%) satisfied:\tPASS")
+
+	// limits are compared with unrounded percentage, total is 23.33%
+	result = Analyze(Config{MaxIgnored: MaxIgnored{Total: 23}}, stats, nil)
+	assert.False(t, result.MeetsMaxIgnoredTotal())
+
+	result = Analyze(Config{MaxIgnored: MaxIgnored{Total: 24}}, stats, nil)
+	assert.True(t, result.MeetsMaxIgnoredTotal())
+
+	assert.NotContains(t, humanReport(AnalyzeResult{}), "ignored")
+
+	// file where every statement is ignored counts towards package and total limits
+	stats = []coverage.Stats{
+		{Name: "org/a/foo.go", Total: 10, Covered: 10},
+		{Name: "org/a/gen.go", Ignored: 10, IgnoredByAnnotation: []coverage.AnnotationStats{
+			{Line: 3, Ignored: 10},
+		}},
+	}
+	cfg := Config{
+		Threshold:  Threshold{File: 100, Package: 100, Total: 100},
+		MaxIgnored: MaxIgnored{Package: 40, Total: 40},
+	}
+	result = Analyze(cfg, stats, nil)
+	assert.True(t, result.PassCoverage())
+	assert.False(t, result.Pass())
+	assert.Len(t, result.PackagesAboveMaxIgnored, 1)
+	assert.False(t, result.MeetsMaxIgnoredTotal())
+
+	out = humanReport(result)
+	assert.Contains(t, out, "org/a/gen.go\t10 (100%)\t3 (10)")
+	assert.Contains(t, out, "org/a\t10 (50%)\t40%")
+}
+
+func Test_ReportRatchet(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	ReportForHuman(buf, AnalyzeResult{})
+	assert.NotContains(t, buf.String(), "Ratchet")
+
+	result := AnalyzeResult{
+		HasRatchet: true,
+		RatchetRegressions: []RatchetRegression{{
+			Current: coverage.Stats{Name: "org/a/foo.go", Total: 10, Covered: 5},
+			Stored:  coverage.Stats{Name: "org/a/foo.go", Total: 10, Covered: 8},
+		}},
+	}
+	assert.False(t, result.Pass())
+
+	buf = &bytes.Buffer{}
+	ReportForHuman(buf, result)
+	out := regexp.MustCompile("\t+").ReplaceAllString(buf.String(), "\t")
+	assert.Contains(t, out, "Ratchet coverage satisfied:\tFAIL")
+	assert.Contains(t, out, "regressed:\tcoverage:\tratchet:")
+	assert.Contains(t, out, "org/a/foo.go\t50.0% (5/10)\t80.0% (8/10)")
+
+	var report struct {
+		Ratchet []struct {
+			Name    string `json:"name"`
+			Current struct {
+				Covered int `json:"covered"`
+			} `json:"current"`
+			Stored struct {
+				Covered int `json:"covered"`
+			} `json:"stored"`
+		} `json:"ratchet-regressions"`
+	}
+
+	buf = &bytes.Buffer{}
+	assert.NoError(t, ReportForJSON(buf, result))
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.Len(t, report.Ratchet, 1)
+	assert.Equal(t, "org/a/foo.go", report.Ratchet[0].Name)
+	assert.Equal(t, 5, report.Ratchet[0].Current.Covered)
+	assert.Equal(t, 8, report.Ratchet[0].Stored.Covered)
+}
+
+func Test_ReportExcludedBySource(t *testing.T) {
+	t.Parallel()
+
+	result := AnalyzeResult{
+		FilesExcludedBySource: []coverage.Exclusion{
+			{Name: "pkg/foo.go", By: coverage.IgnoreFileText, Reason: "generated code"},
+			{Name: "bar/bar.go", By: coverage.IgnorePackageText, Reason: "experimental"},
+			{Name: "foo.pb.go", By: coverage.ExcludedGenerated, Reason: "Code generated. DO NOT EDIT."},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	ReportForHuman(buf, result)
+	assert.Contains(t, buf.String(), "Files excluded from coverage by source:")
+	assert.Contains(t, buf.String(), "bar/bar.go\tcoverage-ignore-package\t\texperimental\n"+
+		"  foo.pb.go\tgenerated\t\t\tCode generated. DO NOT EDIT.\n"+
+		"  pkg/foo.go\tcoverage-ignore-file\t\tgenerated code")
+
+	buf = &bytes.Buffer{}
+	ReportForMarkdown(buf, result, "")
+	assert.Contains(t, buf.String(), "<summary>Files excluded by source (3)</summary>")
+	assert.Contains(t, buf.String(), "| `pkg/foo.go` | `coverage-ignore-file` | generated code |")
+
+	buf = &bytes.Buffer{}
+	assert.NoError(t, ReportForJSON(buf, result))
+	assert.Contains(t, buf.String(), `"excluded": "generated"`)
+	assert.Contains(t, buf.String(), `"reason": "experimental"`)
+
+	buf = &bytes.Buffer{}
+	ReportForHuman(buf, AnalyzeResult{})
+	assert.NotContains(t, buf.String(), "directives")
+}
+
+//nolint:paralleltest // must not be parallel because it uses env
+func Test_SetGithubActionOutput(t *testing.T) {
+	if testing.Short() {
+		return
+	}
+
+	t.Run("writing value to output with error", func(t *testing.T) {
+		err := SetOutputValue(errWriter{}, "key", "val")
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		assert.Contains(t, err.Error(), "key")
+	})
+
+	t.Run("no env file", func(t *testing.T) {
+		t.Setenv(GaOutputFileEnv, "")
+
+		err := SetGithubActionOutput(AnalyzeResult{}, "")
+		assert.Error(t, err)
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		testFile := t.TempDir() + "/ga.output"
+
+		t.Setenv(GaOutputFileEnv, testFile)
+
+		err := SetGithubActionOutput(AnalyzeResult{}, "")
+		assert.NoError(t, err)
+
+		contentBytes, err := os.ReadFile(testFile)
+		assert.NoError(t, err)
+
+		content := string(contentBytes)
+		assert.Equal(t, 1, strings.Count(content, GaOutputTotalCoverage))
 		assert.Equal(t, 1, strings.Count(content, GaOutputBadgeColor))
 		assert.Equal(t, 1, strings.Count(content, GaOutputBadgeText))
 		assert.Equal(t, 1, strings.Count(content, GaOutputReport))
 	})
+
+	t.Run("total coverage is rounded down", func(t *testing.T) {
+		testFile := t.TempDir() + "/ga.output"
+
+		t.Setenv(GaOutputFileEnv, testFile)
+
+		result := AnalyzeResult{TotalStats: coverage.Stats{Total: 2000, Covered: 1999}}
+		assert.NoError(t, SetGithubActionOutput(result, ""))
+
+		contentBytes, err := os.ReadFile(testFile)
+		assert.NoError(t, err)
+		assert.Contains(t, string(contentBytes), GaOutputTotalCoverage+"=99\n")
+		assert.Contains(t, string(contentBytes), GaOutputBadgeText+"=99%\n")
+	})
+}
+
+func Test_ReportForGitlab(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	ReportForGitlab(buf, AnalyzeResult{TotalStats: coverage.Stats{Total: 3, Covered: 2}})
+	assert.Equal(t, "Coverage: 66.6%\n", buf.String())
+}
+
+func Test_ReportForGitlabCodeQuality(t *testing.T) {
+	t.Parallel()
+
+	type issue struct {
+		CheckName   string `json:"check_name"`
+		Fingerprint string `json:"fingerprint"`
+		Location    struct {
+			Path  string `json:"path"`
+			Lines struct {
+				Begin int `json:"begin"`
+				End   int `json:"end"`
+			} `json:"lines"`
+		} `json:"location"`
+	}
+
+	decode := func(t *testing.T, result AnalyzeResult) []issue {
+		t.Helper()
+
+		buf := &bytes.Buffer{}
+		assert.NoError(t, ReportForGitlabCodeQuality(buf, result))
+
+		var issues []issue
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &issues))
+
+		return issues
+	}
+
+	t.Run("no issues", func(t *testing.T) {
+		t.Parallel()
+
+		issues := decode(t, Analyze(Config{}, randStats(prefix, 100, 100), nil))
+		assert.NotNil(t, issues)
+		assert.Empty(t, issues)
+	})
+
+	t.Run("issues", func(t *testing.T) {
+		t.Parallel()
+
+		result := AnalyzeResult{
+			Threshold: Threshold{NewCode: 100},
+			FilesBelowThreshold: []coverage.Stats{
+				{Name: "org/pkg/foo.go", Total: 4, Covered: 2, Threshold: 60},
+			},
+			FilesWithMissingExplanations: []coverage.Stats{
+				{Name: "org/pkg/bar.go", AnnotationsWithoutComments: []int{9}},
+			},
+			HasNewCode: true,
+			NewCode: []coverage.Stats{
+				{Name: "org/pkg/foo.go", Total: 3, Covered: 1, UncoveredLines: []int{5, 6}},
+			},
+		}
+
+		issues := decode(t, result)
+		assert.Len(t, issues, 3)
+		assert.Equal(t, "file-coverage-below-threshold", issues[0].CheckName)
+		assert.Equal(t, "org/pkg/foo.go", issues[0].Location.Path)
+		assert.Equal(t, 1, issues[0].Location.Lines.Begin)
+		assert.Equal(t, "missing-coverage-ignore-explanation", issues[1].CheckName)
+		assert.Equal(t, 9, issues[1].Location.Lines.Begin)
+		assert.Equal(t, "changed-lines-not-covered", issues[2].CheckName)
+		assert.Equal(t, 5, issues[2].Location.Lines.Begin)
+		assert.Equal(t, 6, issues[2].Location.Lines.End)
+
+		// fingerprints should be unique, and should not depend on coverage values
+		assert.NotEqual(t, issues[0].Fingerprint, issues[2].Fingerprint)
+
+		result.FilesBelowThreshold[0].Covered = 1
+		assert.Equal(t, issues[0].Fingerprint, decode(t, result)[0].Fingerprint)
+	})
+
+	t.Run("functions", func(t *testing.T) {
+		t.Parallel()
+
+		result := AnalyzeResult{
+			FunctionsBelowThreshold: coverage.StatsFunctions([]coverage.Stats{{
+				Name: "org/pkg/foo.go",
+				Functions: []coverage.FuncStats{
+					{Name: "Foo", StartLine: 3, EndLine: 8, Total: 4, Covered: 2},
+				},
+			}}),
+		}
+
+		issues := decode(t, result)
+		assert.Len(t, issues, 1)
+		assert.Equal(t, "function-coverage-below-threshold", issues[0].CheckName)
+		assert.Equal(t, "org/pkg/foo.go", issues[0].Location.Path)
+		assert.Equal(t, 3, issues[0].Location.Lines.Begin)
+	})
+
+	t.Run("packages and total", func(t *testing.T) {
+		t.Parallel()
+
+		result := AnalyzeResult{
+			Threshold: Threshold{Total: 80},
+			Files: []coverage.Stats{
+				{Name: "org/pkg/foo.go"},
+				{Name: "org/pkg/bar.go"},
+			},
+			PackagesBelowThreshold: []coverage.Stats{
+				{Name: "org/pkg", Total: 4, Covered: 2, Threshold: 60},
+				{Name: "org/other", Total: 4, Covered: 2, Threshold: 60},
+			},
+			TotalStats: coverage.Stats{Total: 8, Covered: 4},
+		}
+
+		issues := decode(t, result)
+		assert.Len(t, issues, 3)
+		assert.Equal(t, "package-coverage-below-threshold", issues[0].CheckName)
+		assert.Equal(t, "go.mod", issues[0].Location.Path) // package without files
+		assert.Equal(t, 1, issues[0].Location.Lines.Begin)
+		assert.Equal(t, "package-coverage-below-threshold", issues[1].CheckName)
+		assert.Equal(t, "org/pkg/bar.go", issues[1].Location.Path)
+		assert.Equal(t, 1, issues[1].Location.Lines.Begin)
+		assert.Equal(t, "total-coverage-below-threshold", issues[2].CheckName)
+		assert.Equal(t, "go.mod", issues[2].Location.Path)
+		assert.Equal(t, 1, issues[2].Location.Lines.Begin)
+		assert.NotEqual(t, issues[0].Fingerprint, issues[2].Fingerprint)
+	})
+
+	t.Run("annotations", func(t *testing.T) {
+		t.Parallel()
+
+		result := AnalyzeResult{
+			FilesWithUnmatchedAnnotations: []coverage.Stats{
+				{Name: "org/pkg/foo.go", UnmatchedAnnotations: []int{10, 20}},
+			},
+			FilesWithExpiredAnnotations: []coverage.Stats{
+				{Name: "org/pkg/foo.go", ExpiredAnnotations: []int{30}},
+			},
+			FilesWithMissingIssues: []coverage.Stats{
+				{Name: "org/pkg/bar.go", AnnotationsWithoutIssue: []int{40}},
+			},
+		}
+
+		issues := decode(t, result)
+		assert.Len(t, issues, 4)
+		assert.Equal(t, "unmatched-coverage-ignore-annotation", issues[0].CheckName)
+		assert.Equal(t, "org/pkg/foo.go", issues[0].Location.Path)
+		assert.Equal(t, 10, issues[0].Location.Lines.Begin)
+		assert.Equal(t, 20, issues[1].Location.Lines.Begin)
+		assert.NotEqual(t, issues[0].Fingerprint, issues[1].Fingerprint)
+		assert.Equal(t, "expired-coverage-ignore-annotation", issues[2].CheckName)
+		assert.Equal(t, 30, issues[2].Location.Lines.Begin)
+		assert.Equal(t, "missing-coverage-ignore-issue", issues[3].CheckName)
+		assert.Equal(t, "org/pkg/bar.go", issues[3].Location.Path)
+		assert.Equal(t, 40, issues[3].Location.Lines.Begin)
+	})
+
+	t.Run("result is not modified", func(t *testing.T) {
+		t.Parallel()
+
+		result := Analyze(Config{Threshold: Threshold{File: 60}}, randStats(prefix, 0, 50), nil)
+		files := copyStats(result.FilesBelowThreshold)
+
+		decode(t, result)
+		assert.Equal(t, files, result.FilesBelowThreshold)
+	})
+
+	t.Run("failing writer", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Error(t, ReportForGitlabCodeQuality(errWriter{}, AnalyzeResult{}))
+	})
+}
+
+//nolint:paralleltest // must not be parallel because it uses env
+func Test_SetGitlabOutput(t *testing.T) {
+	if testing.Short() {
+		return
+	}
+
+	t.Run("invalid dir", func(t *testing.T) {
+		t.Setenv(GlOutputDirEnv, t.TempDir()+"/not-exist")
+
+		err := SetGitlabOutput(AnalyzeResult{})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid dotenv file", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv(GlOutputDirEnv, dir)
+		assert.NoError(t, os.Mkdir(filepath.Join(dir, GlDotenvFile), 0o755))
+
+		err := SetGitlabOutput(AnalyzeResult{})
+		assert.Error(t, err)
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv(GlOutputDirEnv, dir)
+
+		err := SetGitlabOutput(AnalyzeResult{TotalStats: coverage.Stats{Total: 10, Covered: 9}})
+		assert.NoError(t, err)
+
+		contentBytes, err := os.ReadFile(filepath.Join(dir, GlDotenvFile))
+		assert.NoError(t, err)
+		assert.Equal(t, "TOTAL_COVERAGE=90\nBADGE_COLOR=#97ca00\nBADGE_TEXT=90%\n", string(contentBytes))
+
+		contentBytes, err = os.ReadFile(filepath.Join(dir, GlCodeQualityFile))
+		assert.NoError(t, err)
+		assert.Equal(t, "[]\n", string(contentBytes))
+	})
+
+	t.Run("total coverage is rounded down", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv(GlOutputDirEnv, dir)
+
+		err := SetGitlabOutput(AnalyzeResult{TotalStats: coverage.Stats{Total: 2000, Covered: 1999}})
+		assert.NoError(t, err)
+
+		contentBytes, err := os.ReadFile(filepath.Join(dir, GlDotenvFile))
+		assert.NoError(t, err)
+		assert.Contains(t, string(contentBytes), "TOTAL_COVERAGE=99\n")
+		assert.Contains(t, string(contentBytes), "BADGE_TEXT=99%\n")
+	})
+}
+
+func Test_ReportForMarkdown(t *testing.T) {
+	t.Parallel()
+
+	t.Run("pass", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		result := Analyze(Config{Threshold: Threshold{File: 10, Total: 10}}, randStats(prefix, 100, 100), nil)
+		ReportForMarkdown(buf, result, "")
+
+		assert.Contains(t, buf.String(), "| File coverage threshold | 10% | :white_check_mark: PASS |")
+		assert.Contains(t, buf.String(), "| Total coverage threshold | 10% | :white_check_mark: PASS |")
+		assert.Contains(t, buf.String(), "**Total test coverage:** 100%")
+		assert.NotContains(t, buf.String(), "<details>")
+	})
+
+	t.Run("fail without links", func(t *testing.T) {
+		t.Parallel()
+
+		stats := []coverage.Stats{
+			{Name: "org/pkg/foo.go", Total: 4, Covered: 2, UncoveredLines: []int{3, 4, 7}},
+		}
+
+		buf := &bytes.Buffer{}
+		ReportForMarkdown(buf, Analyze(Config{Threshold: Threshold{File: 60}}, stats, nil), "")
+
+		assert.Contains(t, buf.String(), "| File coverage threshold | 60% | :x: FAIL |")
+		assert.Contains(t, buf.String(), "<summary>Files below threshold (1)</summary>")
+		assert.Contains(t, buf.String(), "| `org/pkg/foo.go` | 50.0% (2/4) | 60% |")
+		assert.Contains(t, buf.String(), "<summary>Files with uncovered lines (1)</summary>")
+		assert.Contains(t, buf.String(), "| `org/pkg/foo.go` | 50.0% (2/4) | 3-4 7 |")
+	})
+
+	t.Run("fail with links", func(t *testing.T) {
+		t.Parallel()
+
+		stats := []coverage.Stats{
+			{Name: "org/pkg/foo.go", Total: 4, Covered: 2, UncoveredLines: []int{3, 4, 7}},
+		}
+
+		buf := &bytes.Buffer{}
+		ReportForMarkdown(buf, Analyze(Config{}, stats, nil), "https://host/o/r/blob/sha/")
+
+		assert.Contains(t, buf.String(),
+			"| [`org/pkg/foo.go`](https://host/o/r/blob/sha/org/pkg/foo.go) | 50.0% (2/4) | "+
+				"[3-4](https://host/o/r/blob/sha/org/pkg/foo.go#L3-L4) "+
+				"[7](https://host/o/r/blob/sha/org/pkg/foo.go#L7) |")
+	})
+
+	t.Run("links in nested module", func(t *testing.T) {
+		t.Parallel()
+
+		if testing.Short() {
+			return
+		}
+
+		repo := t.TempDir()
+		assert.NoError(t, os.Mkdir(filepath.Join(repo, ".git"), 0o755))
+
+		report := func(moduleDir string) string {
+			stats := []coverage.Stats{{
+				Name: "org/pkg/foo.go", Total: 2, Covered: 1, UncoveredLines: []int{7},
+				Path: filepath.Join(moduleDir, "org", "pkg", "foo.go"),
+			}}
+
+			buf := &bytes.Buffer{}
+			ReportForMarkdown(buf, Analyze(Config{}, stats, nil), "https://host/o/r/blob/sha/")
+
+			return buf.String()
+		}
+
+		assert.Contains(t, report(filepath.Join(repo, "sub", "mod")),
+			"| [`org/pkg/foo.go`](https://host/o/r/blob/sha/sub/mod/org/pkg/foo.go) | 50.0% (1/2) | "+
+				"[7](https://host/o/r/blob/sha/sub/mod/org/pkg/foo.go#L7) |")
+		assert.Contains(t, report(repo),
+			"[`org/pkg/foo.go`](https://host/o/r/blob/sha/org/pkg/foo.go)")
+
+		// module outside of git repository
+		assert.Contains(t, report(t.TempDir()),
+			"[`org/pkg/foo.go`](https://host/o/r/blob/sha/org/pkg/foo.go)")
+	})
+
+	t.Run("result is not modified", func(t *testing.T) {
+		t.Parallel()
+
+		result := Analyze(Config{Threshold: Threshold{File: 60}}, randStats(prefix, 0, 50), nil)
+		files := copyStats(result.FilesBelowThreshold)
+
+		ReportForMarkdown(io.Discard, result, "")
+		assert.Equal(t, files, result.FilesBelowThreshold)
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		t.Parallel()
+
+		base := []coverage.Stats{{Name: "org/pkg/foo.go", Total: 4, Covered: 4, CoveredLines: []int{3, 4}}}
+		stats := []coverage.Stats{
+			{Name: "org/pkg/foo.go", Total: 4, Covered: 2, UncoveredLines: []int{3, 4}},
+			{Name: "org/pkg/bar.go", Total: 1, Covered: 0, UncoveredLines: []int{1}},
+		}
+
+		buf := &bytes.Buffer{}
+		ReportForMarkdown(buf, Analyze(Config{}, stats, base), "")
+
+		assert.Contains(t, buf.String(), "### Coverage difference compared to the base")
+		assert.Contains(t, buf.String(), "with 3 lines missing coverage")
+		assert.Contains(t, buf.String(), "| `org/pkg/foo.go` | 2 | 50.0% (2/4) | 100% (4/4) | 3-4 |")
+		assert.Contains(t, buf.String(), "| `org/pkg/bar.go` | 1 |  0.0% (0/1) | / |  |")
+
+		buf.Reset()
+		ReportForMarkdown(buf, Analyze(Config{}, base, base), "")
+		assert.Contains(t, buf.String(), "No coverage changes in any files compared to the base.")
+	})
+}
+
+//nolint:paralleltest // must not be parallel because it uses env
+func Test_SetGithubStepSummary(t *testing.T) {
+	if testing.Short() {
+		return
+	}
+
+	t.Run("no env file", func(t *testing.T) {
+		t.Setenv(GaStepSummaryEnv, "")
+
+		assert.NoError(t, SetGithubStepSummary(AnalyzeResult{}))
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		t.Setenv(GaStepSummaryEnv, t.TempDir())
+
+		assert.Error(t, SetGithubStepSummary(AnalyzeResult{}))
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		testFile := t.TempDir() + "/summary.md"
+		t.Setenv(GaStepSummaryEnv, testFile)
+		t.Setenv("GITHUB_SERVER_URL", "https://github.com")
+		t.Setenv("GITHUB_REPOSITORY", "owner/repo")
+		t.Setenv("GITHUB_SHA", "abc")
+
+		stats := []coverage.Stats{{Name: "org/foo.go", Total: 2, Covered: 1, UncoveredLines: []int{5}}}
+		assert.NoError(t, SetGithubStepSummary(Analyze(Config{}, stats, nil)))
+
+		contentBytes, err := os.ReadFile(testFile)
+		assert.NoError(t, err)
+		assert.Contains(t, string(contentBytes), "## Test coverage report")
+		assert.Contains(t, string(contentBytes), "https://github.com/owner/repo/blob/abc/org/foo.go#L5")
+	})
+}
+
+func Test_ReportForPRComment(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		return
+	}
+
+	var (
+		mx     sync.Mutex
+		bodies []string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mx.Lock()
+		defer mx.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method {
+		case http.MethodGet:
+			comments := []map[string]any{}
+			for i, b := range bodies {
+				comments = append(comments, map[string]any{"id": i + 1, "body": b})
+			}
+
+			json.NewEncoder(w).Encode(comments) //nolint:errcheck,errchkjson // relax
+		case http.MethodPost, http.MethodPatch:
+			c := map[string]any{}
+			json.NewDecoder(r.Body).Decode(&c) //nolint:errcheck // relax
+
+			if r.Method == http.MethodPost {
+				bodies = append(bodies, c["body"].(string)) //nolint:forcetypeassert // relax
+			} else {
+				bodies[0] = c["body"].(string) //nolint:forcetypeassert // relax
+			}
+
+			json.NewEncoder(w).Encode(c) //nolint:errcheck,errchkjson // relax
+		}
+	}))
+	defer srv.Close()
+
+	cfg := prcomment.Github{
+		Token:       "token",
+		Owner:       "owner",
+		Repository:  "repo",
+		PullRequest: 3,
+		BaseURL:     srv.URL,
+	}
+
+	stats := []coverage.Stats{{Name: "org/foo.go", Total: 2, Covered: 1, UncoveredLines: []int{5}}}
+	base := []coverage.Stats{{Name: "org/foo.go", Total: 2, Covered: 2}}
+	result := Analyze(Config{Threshold: Threshold{Total: 60}}, stats, base)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, ReportForPRComment(buf, result, cfg))
+	assert.Contains(t, buf.String(), "Pull request #3 commented with coverage report.")
+
+	buf = &bytes.Buffer{}
+	assert.NoError(t, ReportForPRComment(buf, result, cfg))
+	assert.Contains(t, buf.String(), "Pull request #3 already has comment with the same coverage report.")
+
+	mx.Lock()
+	assert.Len(t, bodies, 1)
+	assert.True(t, strings.HasPrefix(bodies[0], prcomment.Marker))
+	assert.Contains(t, bodies[0], "## Test coverage report")
+	assert.Contains(t, bodies[0], "| Total coverage threshold | 60% | :x: FAIL |")
+	assert.Contains(t, bodies[0], "### Coverage difference compared to the base")
+	mx.Unlock()
+
+	cfg.BaseURL = "://invalid"
+	assert.Error(t, ReportForPRComment(io.Discard, result, cfg))
+
+	// comment is skipped when pull request is not known
+	cfg.PullRequest = 0
+	buf = &bytes.Buffer{}
+	assert.NoError(t, ReportForPRComment(buf, result, cfg))
+	assert.Contains(t, buf.String(), "Pull request comment skipped, because pull request number is not set.")
+}
+
+func Test_ReportForGithubCheckRun(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		return
+	}
+
+	var body struct {
+		Conclusion string `json:"conclusion"`
+		Output     struct {
+			Title       string `json:"title"`
+			Summary     string `json:"summary"`
+			Annotations []struct {
+				Path      string `json:"path"`
+				StartLine int    `json:"start_line"`
+				EndLine   int    `json:"end_line"`
+				Title     string `json:"title"`
+			} `json:"annotations"`
+		} `json:"output"`
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body) //nolint:errcheck // relax
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":1,"html_url":"https://github.com/owner/repo/runs/1"}`)) //nolint:errcheck // relax
+	}))
+	defer srv.Close()
+
+	cfg := checkrun.Github{
+		Token:      "token",
+		Owner:      "owner",
+		Repository: "repo",
+		HeadSHA:    "abc",
+		BaseURL:    srv.URL,
+	}
+
+	stats := []coverage.Stats{
+		{Name: "org/foo.go", Total: 10, Covered: 5, UncoveredLines: []int{3, 4, 5, 9}},
+		{Name: "org/bar.go", Total: 10, Covered: 1}, // uncovered lines are not known
+		{Name: "org/baz.go", Total: 10, Covered: 10, AnnotationsWithoutComments: []int{7}},
+		{Name: "org/qux.go", Total: 10, Covered: 10, UnmatchedAnnotations: []int{2}},
+		{Name: "org/quux.go", Total: 10, Covered: 10, ExpiredAnnotations: []int{4}, AnnotationsWithoutIssue: []int{6}},
+	}
+	result := Analyze(Config{
+		Threshold:              Threshold{File: 60},
+		ForceAnnotationComment: true,
+		ForceAnnotationIssue:   true,
+	}, stats, nil)
+	result.HasNewCode = true
+	result.Threshold.NewCode = 100
+	result.NewCode = []coverage.Stats{{Name: "org/foo.go", Total: 2, Covered: 1, UncoveredLines: []int{9}}}
+
+	filesBelowThreshold := copyStats(result.FilesBelowThreshold)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, ReportForGithubCheckRun(buf, result, cfg))
+	assert.Contains(t, buf.String(), "GitHub check run created: https://github.com/owner/repo/runs/1")
+	assert.Equal(t, filesBelowThreshold, result.FilesBelowThreshold) // result is not modified
+
+	assert.Equal(t, "failure", body.Conclusion)
+	assert.Equal(t, "FAIL: total test coverage 72.0% (36/50)", body.Output.Title)
+	assert.Contains(t, body.Output.Summary, "## Test coverage report")
+
+	type annotation struct {
+		Path       string
+		Start, End int
+		Title      string
+	}
+
+	annotations := []annotation{}
+	for _, a := range body.Output.Annotations {
+		annotations = append(annotations, annotation{a.Path, a.StartLine, a.EndLine, a.Title})
+	}
+
+	assert.Equal(t, []annotation{
+		{"org/bar.go", 1, 1, "File test coverage below threshold"},
+		{"org/foo.go", 3, 5, "File test coverage below threshold"},
+		{"org/foo.go", 9, 9, "File test coverage below threshold"},
+		{"org/baz.go", 7, 7, "Missing explanation for coverage-ignore"},
+		{"org/qux.go", 2, 2, "Unmatched coverage-ignore range annotation"},
+		{"org/quux.go", 4, 4, "Expired coverage-ignore annotation"},
+		{"org/quux.go", 6, 6, "Missing issue reference for coverage-ignore"},
+		{"org/foo.go", 9, 9, "Changed lines not covered by tests"},
+	}, annotations)
+
+	cfg.BaseURL = "://invalid"
+	assert.Error(t, ReportForGithubCheckRun(io.Discard, result, cfg))
 }
 
 func Test_ReportUncoveredLines(t *testing.T) {
@@ -420,7 +2169,7 @@ func Test_ReportUncoveredLines(t *testing.T) {
 		"a.go\t\t0%\t\t1-3\n",
 		"b.go\t\t0%\t\t3 5 7\n",
 		"c.go\t\t20%\t\t1 4 10\n",
-		"d.go\t\t20.5%\t\t7-9\n",
+		"d.go\t\t20.4%\t\t7-9\n", // 20.45.. is rounded down
 	})
 
 	// when result passes, there should be no output
@@ -0,0 +1,201 @@
+package testcoverage
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+)
+
+// Reporter writes analyze result to writer, which is either output of the check
+// or file set in report configuration.
+type Reporter interface {
+	Report(w io.Writer, result AnalyzeResult) error
+}
+
+// ReporterFunc is an adapter which allows use of ordinary function as Reporter.
+type ReporterFunc func(w io.Writer, result AnalyzeResult) error
+
+func (f ReporterFunc) Report(w io.Writer, result AnalyzeResult) error {
+	return f(w, result)
+}
+
+// ReporterFactory creates reporter for given configuration.
+type ReporterFactory func(cfg Config) Reporter
+
+const (
+	ReportTypeHuman        = "human"
+	ReportTypeGithubAction = "github-action"
+	ReportTypeGitlab       = "gitlab"
+	ReportTypeHTML         = "html"
+	ReportTypeCobertura    = "cobertura"
+	ReportTypeLcov         = "lcov"
+	ReportTypeJSON         = "json"
+	ReportTypeSarif        = "sarif"
+	ReportTypeJUnit        = "junit"
+	ReportTypeMarkdown     = "markdown"
+	ReportTypePRComment    = "pr-comment"
+	ReportTypeCheckRun     = "github-check-run"
+)
+
+//nolint:gochecknoglobals // relax
+var (
+	reportersMx sync.RWMutex
+	reporters   = map[string]ReporterFactory{
+		ReportTypeHuman: reporterOf(withNoError(ReportForHuman)),
+		ReportTypeGithubAction: func(cfg Config) Reporter {
+			return ReporterFunc(func(w io.Writer, result AnalyzeResult) error {
+				// workflow commands are not needed when issues are annotated by check run
+				return reportForGithubAction(w, result, cfg.CheckRun.Token == "")
+			})
+		},
+		ReportTypeGitlab:    reporterOf(reportForGitlab),
+		ReportTypeHTML:      reporterOf(ReportForHTML),
+		ReportTypeCobertura: reporterOf(ReportForCobertura),
+		ReportTypeLcov:      reporterOf(ReportForLcov),
+		ReportTypeJSON:      reporterOf(ReportForJSON),
+		ReportTypeJUnit:     reporterOf(ReportForJUnit),
+		ReportTypeMarkdown: reporterOf(func(w io.Writer, result AnalyzeResult) error {
+			ReportForMarkdown(w, result, "")
+			return nil
+		}),
+		ReportTypeSarif: func(cfg Config) Reporter {
+			return ReporterFunc(func(w io.Writer, result AnalyzeResult) error {
+				return ReportForSarif(w, result, cfg.SarifUncoveredLines)
+			})
+		},
+		ReportTypePRComment: func(cfg Config) Reporter {
+			return ReporterFunc(func(w io.Writer, result AnalyzeResult) error {
+				return ReportForPRComment(w, result, cfg.PRComment)
+			})
+		},
+		ReportTypeCheckRun: func(cfg Config) Reporter {
+			return ReporterFunc(func(w io.Writer, result AnalyzeResult) error {
+				return ReportForGithubCheckRun(w, result, cfg.CheckRun)
+			})
+		},
+	}
+)
+
+// RegisterReporter registers reporter factory under report type, so it can be
+// selected with `reports` configuration. Registering already existing type
+// replaces its reporter.
+func RegisterReporter(reportType string, factory ReporterFactory) {
+	reportersMx.Lock()
+	defer reportersMx.Unlock()
+
+	reporters[reportType] = factory
+}
+
+func reporterFactory(reportType string) (ReporterFactory, bool) {
+	reportersMx.RLock()
+	defer reportersMx.RUnlock()
+
+	f, ok := reporters[reportType]
+
+	return f, ok
+}
+
+func reporterOf(fn func(w io.Writer, result AnalyzeResult) error) ReporterFactory {
+	return func(Config) Reporter { return ReporterFunc(fn) }
+}
+
+func withNoError(fn func(w io.Writer, result AnalyzeResult)) func(io.Writer, AnalyzeResult) error {
+	return func(w io.Writer, result AnalyzeResult) error {
+		fn(w, result)
+		return nil
+	}
+}
+
+func reportForGithubAction(w io.Writer, result AnalyzeResult, withCommands bool) error {
+	if withCommands {
+		ReportForGithubAction(w, result)
+	}
+
+	report := &bytes.Buffer{}
+	ReportForHuman(report, result)
+
+	return errors.Join(
+		SetGithubActionOutput(result, report.String()),
+		SetGithubStepSummary(result),
+	)
+}
+
+func reportForGitlab(w io.Writer, result AnalyzeResult) error {
+	ReportForGitlab(w, result)
+
+	return SetGitlabOutput(result)
+}
+
+// reportsFromConfig returns reports which should be made. Human report is always
+// made unless it is configured in `reports`, and reports set with dedicated options
+// (eg. `html-report`) are appended to configured ones. Disabled reports are dropped.
+func reportsFromConfig(cfg Config) []Report {
+	reports := slices.Clone(cfg.Reports)
+	if !slices.ContainsFunc(reports, isHumanReport) {
+		reports = slices.Insert(reports, 0, Report{Type: ReportTypeHuman})
+	}
+
+	add := func(r Report, enabled bool) {
+		if enabled && !slices.Contains(reports, r) {
+			reports = append(reports, r)
+		}
+	}
+
+	add(Report{Type: ReportTypeHTML, File: cfg.HTMLReport}, cfg.HTMLReport != "")
+	add(Report{Type: ReportTypeCobertura, File: cfg.CoberturaFile}, cfg.CoberturaFile != "")
+	add(Report{Type: ReportTypeLcov, File: cfg.LcovFile}, cfg.LcovFile != "")
+	add(Report{Type: ReportTypeJSON, File: cfg.JSONReport}, cfg.JSONReport != "")
+	add(Report{Type: ReportTypeSarif, File: cfg.SarifFile}, cfg.SarifFile != "")
+	add(Report{Type: ReportTypeJUnit, File: cfg.JUnitFile}, cfg.JUnitFile != "")
+	add(Report{Type: ReportTypeGithubAction}, cfg.GithubActionOutput)
+	add(Report{Type: ReportTypeGitlab}, cfg.GitlabOutput)
+	add(Report{Type: ReportTypePRComment}, cfg.PRComment.Token != "")
+	add(Report{Type: ReportTypeCheckRun}, cfg.CheckRun.Token != "")
+
+	return slices.DeleteFunc(reports, func(r Report) bool { return r.Disabled })
+}
+
+func isHumanReport(r Report) bool {
+	return r.Type == ReportTypeHuman
+}
+
+// reportErrorMsg returns message of error which occurred while making report.
+func reportErrorMsg(reportType string) string {
+	switch reportType {
+	case ReportTypeGithubAction:
+		return "failed setting github action output"
+	case ReportTypeGitlab:
+		return "failed setting gitlab output"
+	default:
+		return fmt.Sprintf("failed to save %s report", reportType)
+	}
+}
+
+// makeReport makes report with reporter of report type. Report is written to
+// file when it is set, otherwise it is written to w.
+func makeReport(w io.Writer, cfg Config, r Report, result AnalyzeResult) error {
+	factory, ok := reporterFactory(r.Type)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownReportType, r.Type)
+	}
+
+	if r.File == "" {
+		out := bufio.NewWriter(w)
+		defer out.Flush()
+
+		return factory(cfg).Report(out, result) //nolint:wrapcheck // error is wrapped at level above
+	}
+
+	buf := &bytes.Buffer{}
+	if err := factory(cfg).Report(buf, result); err != nil {
+		return err //nolint:wrapcheck // error is wrapped at level above
+	}
+
+	//nolint:mnd,wrapcheck,gosec // relax
+	return os.WriteFile(r.File, buf.Bytes(), 0o644)
+}
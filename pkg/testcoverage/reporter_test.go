@@ -0,0 +1,166 @@
+package testcoverage_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage"
+)
+
+func TestReporters(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		return
+	}
+
+	t.Run("human report is kept", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:   profileOK,
+			SourceDir: sourceDir,
+			Reports:   []Report{{Type: ReportTypeJSON}},
+		}
+		pass, err := Check(buf, cfg)
+		assert.True(t, pass)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(buf.String(), "Total test coverage:"))
+		assert.Contains(t, buf.String(), `"version": 1`)
+	})
+
+	t.Run("human report disabled", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:   profileOK,
+			SourceDir: sourceDir,
+			Reports:   []Report{{Type: ReportTypeHuman, Disabled: true}, {Type: ReportTypeJSON}},
+		}
+		pass, err := Check(buf, cfg)
+		assert.True(t, pass)
+		assert.NoError(t, err)
+		assert.NotContains(t, buf.String(), "Total test coverage:")
+		assert.Contains(t, buf.String(), `"version": 1`)
+	})
+
+	t.Run("multiple reports", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:   profileOK,
+			SourceDir: sourceDir,
+			Reports: []Report{
+				{Type: ReportTypeHuman},
+				{Type: ReportTypeLcov, File: dir + "/lcov.info"},
+				{Type: ReportTypeJUnit, File: dir + "/junit.xml"},
+			},
+		}
+		pass, err := Check(buf, cfg)
+		assert.True(t, pass)
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), "Total test coverage:")
+		assert.FileExists(t, dir+"/lcov.info")
+		assert.FileExists(t, dir+"/junit.xml")
+	})
+
+	t.Run("duplicated reports", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:   profileOK,
+			SourceDir: sourceDir,
+			Reports:   []Report{{Type: ReportTypeHuman}, {Type: ReportTypeHuman}},
+		}
+		pass, err := Check(buf, cfg)
+		assert.True(t, pass)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, bytes.Count(buf.Bytes(), []byte("Total test coverage:")))
+
+		file := t.TempDir() + "/report.json"
+		cfg.Reports = []Report{{Type: ReportTypeHuman, Disabled: true}, {Type: ReportTypeJSON, File: file}}
+		cfg.JSONReport = file
+		buf.Reset()
+
+		pass, err = Check(buf, cfg)
+		assert.True(t, pass)
+		assert.NoError(t, err)
+		assert.Empty(t, buf.String())
+		assert.FileExists(t, file)
+	})
+
+	t.Run("custom reporter", func(t *testing.T) {
+		t.Parallel()
+
+		RegisterReporter("test-custom", func(cfg Config) Reporter {
+			return ReporterFunc(func(w io.Writer, result AnalyzeResult) error {
+				_, err := fmt.Fprintf(w, "custom: %s %d", cfg.Profile, result.TotalStats.Total)
+				return err //nolint:wrapcheck // relax
+			})
+		})
+
+		file := t.TempDir() + "/custom.txt"
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:   profileOK,
+			SourceDir: sourceDir,
+			Reports:   []Report{{Type: "test-custom"}, {Type: "test-custom", File: file}},
+		}
+		assert.NoError(t, cfg.Validate())
+
+		pass, err := Check(buf, cfg)
+		assert.True(t, pass)
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), "custom: "+profileOK)
+
+		content, err := os.ReadFile(file)
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), "custom: "+profileOK)
+	})
+
+	t.Run("custom reporter error", func(t *testing.T) {
+		t.Parallel()
+
+		errTest := errors.New("test error")
+		RegisterReporter("test-error", func(Config) Reporter {
+			return ReporterFunc(func(io.Writer, AnalyzeResult) error { return errTest })
+		})
+
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:   profileOK,
+			SourceDir: sourceDir,
+			Reports:   []Report{{Type: "test-error", File: t.TempDir() + "/out"}},
+		}
+		pass, err := Check(buf, cfg)
+		assert.False(t, pass)
+		assert.ErrorIs(t, err, errTest)
+		assert.Contains(t, err.Error(), "failed to save test-error report")
+	})
+
+	t.Run("unknown report type", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		cfg := Config{
+			Profile:   profileOK,
+			SourceDir: sourceDir,
+			Reports:   []Report{{Type: "does-not-exist"}},
+		}
+		pass, err := Check(buf, cfg)
+		assert.False(t, pass)
+		assert.ErrorIs(t, err, ErrUnknownReportType)
+	})
+}
@@ -0,0 +1,28 @@
+mode: set
+example.com/annotations/targets.go:4.2,4.13 1 1
+example.com/annotations/targets.go:5.3,6.1 1 0
+example.com/annotations/targets.go:8.2,8.10 1 1
+example.com/annotations/targets.go:12.2,12.11 1 1
+example.com/annotations/targets.go:13.3,14.1 1 1
+example.com/annotations/targets.go:15.3,15.14 1 0
+example.com/annotations/targets.go:16.4,17.1 1 0
+example.com/annotations/targets.go:19.3,19.6 1 0
+example.com/annotations/targets.go:22.2,22.10 1 1
+example.com/annotations/targets.go:26.2,26.11 1 1
+example.com/annotations/targets.go:28.3,28.6 1 1
+example.com/annotations/targets.go:30.3,30.6 1 0
+example.com/annotations/targets.go:33.2,33.10 1 1
+example.com/annotations/targets.go:37.2,38.1 2 1
+example.com/annotations/targets.go:39.2,39.9 2 1
+example.com/annotations/targets.go:41.3,41.8 1 1
+example.com/annotations/targets.go:43.3,43.9 1 0
+example.com/annotations/targets.go:46.2,46.10 1 1
+example.com/annotations/targets.go:50.2,50.18 1 1
+example.com/annotations/targets.go:51.3,52.1 1 0
+example.com/annotations/targets.go:53.2,54.1 2 1
+example.com/annotations/targets.go:55.2,55.14 2 1
+example.com/annotations/targets.go:56.3,57.1 1 1
+example.com/annotations/targets.go:59.2,59.10 1 1
+example.com/annotations/targets.go:63.2,63.11 1 1
+example.com/annotations/targets.go:64.3,65.1 1 1
+example.com/annotations/targets.go:67.2,67.10 1 0
@@ -0,0 +1,68 @@
+package annotations
+
+func If(a int) int {
+	if a > 100 { // coverage-ignore
+		a = 100
+	}
+
+	return a
+}
+
+func Else(a int) int {
+	if a > 0 {
+		a++
+	} else { // coverage-ignore
+		if a < -10 {
+			a = 0
+		}
+
+		a--
+	}
+
+	return a
+}
+
+func Case(a int) int {
+	switch a {
+	case 1:
+		a++
+	default: // coverage-ignore
+		a--
+	}
+
+	return a
+}
+
+func Select(c chan int) int {
+	a := 0
+
+	select {
+	case v := <-c:
+		a = v
+	default: // coverage-ignore
+		a = -1
+	}
+
+	return a
+}
+
+func FuncLit(a int) int {
+	reset := func() { // coverage-ignore
+		a = 0
+	}
+	_ = reset
+
+	for range 2 {
+		a++
+	}
+
+	return a
+}
+
+func Line(a int) int {
+	if a > 0 {
+		return a
+	}
+
+	return 0 // coverage-ignore
+}
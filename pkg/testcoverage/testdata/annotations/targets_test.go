@@ -0,0 +1,15 @@
+package annotations
+
+import "testing"
+
+func TestTargets(t *testing.T) {
+	c := make(chan int, 1)
+	c <- 1
+
+	If(1)
+	Else(1)
+	Case(1)
+	Select(c)
+	FuncLit(1)
+	Line(1)
+}
@@ -26,6 +26,20 @@ const (
 	// does not have correct profile items
 	ProfileNOKInvalidData = "invalid_data.profile"
 
+	// binary coverage data (GOCOVERDIR) of small program, built with `go build -cover`
+	// and executed twice
+	CovDataDir = "covdata"
+
+	// text profile of `covdata` made with `go tool covdata textfmt`
+	CovDataProfile = "covdata.profile"
+
+	// module with source annotated with coverage-ignore annotations on
+	// every supported target
+	AnnotationsDir = "annotations"
+
+	// profile of `annotations` module made with `go test -coverprofile`
+	AnnotationsProfile = "annotations.profile"
+
 	// holds valid test coverage breakdown
 	BreakdownOK = "breakdown_ok.testcoverage"
 
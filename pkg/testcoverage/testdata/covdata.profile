@@ -0,0 +1,14 @@
+mode: count
+example.com/covprog/main.go:11.2,11.22 1 2
+example.com/covprog/main.go:12.3,14.1 2 1
+example.com/covprog/main.go:16.2,16.40 1 1
+example.com/covprog/calc/calc.go:4.2,4.11 1 2
+example.com/covprog/calc/calc.go:5.3,6.1 1 1
+example.com/covprog/calc/calc.go:8.2,8.10 1 1
+example.com/covprog/calc/calc.go:12.2,12.9 1 1
+example.com/covprog/calc/calc.go:14.3,14.11 1 0
+example.com/covprog/calc/calc.go:16.3,16.12 1 0
+example.com/covprog/calc/calc.go:19.2,19.10 1 1
+example.com/covprog/calc/calc.go:23.2,23.18 1 0
+example.com/covprog/calc/calc.go:23.20,23.30 1 0
+example.com/covprog/calc/calc.go:24.2,24.12 1 0
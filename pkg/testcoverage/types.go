@@ -7,26 +7,74 @@ import (
 	"strings"
 
 	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/coverage"
+	"github.com/vladopajic/go-test-coverage/v2/pkg/testcoverage/patch"
 )
 
+// Result is result of coverage analysis. It is encoded with stable JSON schema
+// by MarshalJSON.
+type Result = AnalyzeResult
+
 type AnalyzeResult struct {
-	Threshold                    Threshold
-	DiffThreshold                *float64
-	FilesBelowThreshold          []coverage.Stats
-	PackagesBelowThreshold       []coverage.Stats
-	FilesWithUncoveredLines      []coverage.Stats
-	FilesWithMissingExplanations []coverage.Stats
-	TotalStats                   coverage.Stats
-	HasBaseBreakdown             bool
-	Diff                         []FileCoverageDiff
-	DiffPercentage               float64
-	HasFileOverrides             bool
-	HasPackageOverrides          bool
+	Threshold                     Threshold
+	MaxUncovered                  MaxUncovered
+	MaxUncoveredLines             MaxUncovered
+	MaxIgnored                    MaxIgnored
+	FilesAboveMaxIgnored          []coverage.Stats
+	PackagesAboveMaxIgnored       []coverage.Stats
+	DiffThreshold                 *float64
+	FilesBelowThreshold           []coverage.Stats
+	PackagesBelowThreshold        []coverage.Stats
+	FunctionsBelowThreshold       []coverage.Stats
+	FilesWithUncoveredLines       []coverage.Stats
+	FilesWithMissingExplanations  []coverage.Stats
+	FilesWithUnmatchedAnnotations []coverage.Stats
+	ForceAnnotationComment        bool
+	FilesWithExpiredAnnotations   []coverage.Stats
+	FilesWithInvalidAnnotations   []coverage.Stats
+	FilesWithMissingIssues        []coverage.Stats
+	ForceAnnotationIssue          bool
+	TotalStats                    coverage.Stats
+	HasBaseBreakdown              bool
+	Diff                          []FileCoverageDiff
+	DiffPercentage                float64
+	HasFileOverrides              bool
+	HasPackageOverrides           bool
+	HasFunctionOverrides          bool
+	HasNewCode                    bool
+	NewCode                       []coverage.Stats
+	ColdBlockHits                 int
+	FilesWithColdBlocks           []coverage.Stats
+	FilesExcludedBySource         []coverage.Exclusion
+	HasRatchet                    bool
+	RatchetRegressions            []RatchetRegression
+
+	// Files, Packages and Functions hold stats of all files, packages and
+	// functions, where each has threshold which applies to it.
+	Files     []coverage.Stats
+	Packages  []coverage.Stats
+	Functions []coverage.Stats
 }
 
 func (r *AnalyzeResult) Pass() bool {
 	return r.PassCoverage() &&
-		len(r.FilesWithMissingExplanations) == 0
+		len(r.FilesWithMissingExplanations) == 0 &&
+		len(r.FilesWithUnmatchedAnnotations) == 0 &&
+		len(r.FilesWithExpiredAnnotations) == 0 &&
+		len(r.FilesWithInvalidAnnotations) == 0 &&
+		len(r.FilesWithMissingIssues) == 0 &&
+		r.MeetsMaxIgnored()
+}
+
+// MeetsMaxIgnored returns true if statements ignored with annotations do not
+// exceed limits of files, packages and total.
+func (r *AnalyzeResult) MeetsMaxIgnored() bool {
+	return len(r.FilesAboveMaxIgnored) == 0 &&
+		len(r.PackagesAboveMaxIgnored) == 0 &&
+		r.MeetsMaxIgnoredTotal()
+}
+
+func (r *AnalyzeResult) MeetsMaxIgnoredTotal() bool {
+	return r.MaxIgnored.Total == 0 || r.TotalStats.IgnoredPercentage() <= float64(r.MaxIgnored.Total)
 }
 
 // PassCoverage returns true if all coverage thresholds are met, ignoring annotation completeness.
@@ -34,7 +82,30 @@ func (r *AnalyzeResult) PassCoverage() bool {
 	return r.MeetsTotalCoverage() &&
 		len(r.FilesBelowThreshold) == 0 &&
 		len(r.PackagesBelowThreshold) == 0 &&
-		r.MeetsDiffThreshold()
+		len(r.FunctionsBelowThreshold) == 0 &&
+		r.MeetsDiffThreshold() &&
+		r.MeetsNewCodeThreshold() &&
+		len(r.RatchetRegressions) == 0
+}
+
+// HasFileThreshold returns true if coverage of files is checked, either with
+// threshold, limit of uncovered statements or lines, or override rules.
+func (r *AnalyzeResult) HasFileThreshold() bool {
+	return r.Threshold.File > 0 || r.MaxUncovered.File > 0 || r.MaxUncoveredLines.File > 0 ||
+		r.HasFileOverrides
+}
+
+// HasPackageThreshold returns true if coverage of packages is checked, either
+// with threshold, limit of uncovered statements or lines, or override rules.
+func (r *AnalyzeResult) HasPackageThreshold() bool {
+	return r.Threshold.Package > 0 || r.MaxUncovered.Package > 0 ||
+		r.MaxUncoveredLines.Package > 0 || r.HasPackageOverrides
+}
+
+// HasTotalThreshold returns true if total coverage is checked, either with
+// threshold or limit of uncovered statements or lines.
+func (r *AnalyzeResult) HasTotalThreshold() bool {
+	return r.Threshold.Total > 0 || r.MaxUncovered.Total > 0 || r.MaxUncoveredLines.Total > 0
 }
 
 func (r *AnalyzeResult) MeetsDiffThreshold() bool {
@@ -45,8 +116,52 @@ func (r *AnalyzeResult) MeetsDiffThreshold() bool {
 	return *r.DiffThreshold <= r.DiffPercentage
 }
 
+// MeetsNewCodeThreshold returns true if coverage of changed lines satisfies threshold.
+func (r *AnalyzeResult) MeetsNewCodeThreshold() bool {
+	if !r.HasNewCode {
+		return true
+	}
+
+	total := coverage.StatsCalcTotal(r.NewCode)
+
+	return total.Total == 0 || total.CoveredPercentageFNR() >= r.Threshold.NewCode
+}
+
 func (r *AnalyzeResult) MeetsTotalCoverage() bool {
-	return r.TotalStats.Total == 0 || r.TotalStats.CoveredPercentage() >= r.Threshold.Total
+	return r.TotalStats.Total == 0 || meetsThreshold(r.totalStatsWithThreshold())
+}
+
+// totalStatsWithThreshold returns total stats with total threshold and
+// limits of uncovered statements and lines which apply to it.
+func (r *AnalyzeResult) totalStatsWithThreshold() coverage.Stats {
+	s := r.TotalStats
+	s.Threshold = r.Threshold.Total
+	s.MaxUncovered = r.MaxUncovered.Total
+	s.MaxUncoveredLines = r.MaxUncoveredLines.Total
+
+	return s
+}
+
+// meetsThreshold returns true if stats satisfy threshold and limits of uncovered
+// statements and lines which apply to it. Stats where every statement is ignored
+// with annotations always meet threshold.
+func meetsThreshold(s coverage.Stats) bool {
+	if s.Total == 0 && s.Ignored > 0 {
+		return true
+	}
+
+	return s.CoveredPercentageFNR() >= s.Threshold &&
+		(s.MaxUncovered == 0 || s.UncoveredStmtCount() <= s.MaxUncovered) &&
+		(s.MaxUncoveredLines == 0 || s.UncoveredLinesCount() <= s.MaxUncoveredLines)
+}
+
+// valueOr returns value which v points to, or def when v is nil.
+func valueOr[T any](v *T, def T) T {
+	if v == nil {
+		return def
+	}
+
+	return *v
 }
 
 func packageForFile(filename string) string {
@@ -60,24 +175,65 @@ func packageForFile(filename string) string {
 
 func checkCoverageStatsBelowThreshold(
 	coverageStats []coverage.Stats,
-	threshold int,
+	threshold float64,
+	maxUncovered, maxUncoveredLines int,
 	overrideRules []regRule,
 ) []coverage.Stats {
 	var belowThreshold []coverage.Stats
 
-	for _, s := range coverageStats {
-		thr := threshold
+	stats := statsWithThreshold(
+		coverageStats, threshold, maxUncovered, maxUncoveredLines, overrideRules,
+	)
+	for _, s := range stats {
+		if !meetsThreshold(s) {
+			belowThreshold = append(belowThreshold, s)
+		}
+	}
+
+	return belowThreshold
+}
+
+// statsWithThreshold returns copy of stats, where each stats has threshold and limits of
+// uncovered statements and lines that apply to it; either from matching override rule
+// or defaults. Each value is taken from override rule only when rule sets it.
+func statsWithThreshold(
+	coverageStats []coverage.Stats,
+	threshold float64,
+	maxUncovered, maxUncoveredLines int,
+	overrideRules []regRule,
+) []coverage.Stats {
+	result := make([]coverage.Stats, len(coverageStats))
+
+	for i, s := range coverageStats {
+		s.Threshold, s.MaxUncovered, s.MaxUncoveredLines = threshold, maxUncovered, maxUncoveredLines
 		if override, ok := matches(overrideRules, s.Name); ok {
-			thr = override
+			s.Threshold = valueOr(override.threshold, s.Threshold)
+			s.MaxUncovered = valueOr(override.maxUncovered, s.MaxUncovered)
+			s.MaxUncoveredLines = valueOr(override.maxUncoveredLines, s.MaxUncoveredLines)
 		}
 
-		if s.CoveredPercentage() < thr {
-			s.Threshold = thr
-			belowThreshold = append(belowThreshold, s)
+		result[i] = s
+	}
+
+	return result
+}
+
+// checkStatsAboveMaxIgnored returns stats which have larger percentage of
+// ignored statements than limit.
+func checkStatsAboveMaxIgnored(coverageStats []coverage.Stats, limit int) []coverage.Stats {
+	if limit == 0 {
+		return nil
+	}
+
+	var res []coverage.Stats
+
+	for _, s := range coverageStats {
+		if s.IgnoredPercentage() > float64(limit) {
+			res = append(res, s)
 		}
 	}
 
-	return belowThreshold
+	return res
 }
 
 func makePackageStats(coverageStats []coverage.Stats) []coverage.Stats {
@@ -95,6 +251,8 @@ func makePackageStats(coverageStats []coverage.Stats) []coverage.Stats {
 
 		pkgStats.Total += stats.Total
 		pkgStats.Covered += stats.Covered
+		pkgStats.Ignored += stats.Ignored
+		pkgStats.AggregatedUncoveredLines += stats.UncoveredLinesCount()
 		packageStats[pkg] = pkgStats
 	}
 
@@ -104,6 +262,11 @@ func makePackageStats(coverageStats []coverage.Stats) []coverage.Stats {
 type FileCoverageDiff struct {
 	Current coverage.Stats
 	Base    *coverage.Stats
+
+	// NewlyUncoveredLines and NewlyCoveredLines are available only when base
+	// breakdown holds line-level data (breakdown format v2 and later).
+	NewlyUncoveredLines []int
+	NewlyCoveredLines   []int
 }
 
 func calculateStatsDiff(current, base []coverage.Stats) []FileCoverageDiff {
@@ -117,12 +280,51 @@ func calculateStatsDiff(current, base []coverage.Stats) []FileCoverageDiff {
 			continue
 		}
 
-		if b, found := baseSearchMap[s.Name]; found {
-			if sul != b.UncoveredStmtCount() {
-				res = append(res, FileCoverageDiff{Current: s, Base: &b})
-			}
-		} else {
+		b, found := baseSearchMap[s.Name]
+		if !found {
 			res = append(res, FileCoverageDiff{Current: s})
+			continue
+		}
+
+		d := FileCoverageDiff{Current: s, Base: &b}
+
+		if hasLineData(s) && hasLineData(b) {
+			// lines are compared even when number of uncovered statements is the same,
+			// as different lines may have become uncovered
+			d.NewlyUncoveredLines = linesDiff(s.UncoveredLines, b.UncoveredLines)
+			d.NewlyCoveredLines = linesDiff(b.UncoveredLines, s.UncoveredLines)
+
+			if len(d.NewlyUncoveredLines) == 0 && len(d.NewlyCoveredLines) == 0 {
+				continue
+			}
+		} else if sul == b.UncoveredStmtCount() {
+			continue
+		}
+
+		res = append(res, d)
+	}
+
+	return res
+}
+
+// hasLineData returns true when stats hold uncovered lines, which is not
+// the case for stats loaded from legacy breakdown format.
+func hasLineData(s coverage.Stats) bool {
+	return s.UncoveredStmtCount() == 0 || len(s.UncoveredLines) > 0
+}
+
+// linesDiff returns lines from a which are not in b.
+func linesDiff(a, b []int) []int {
+	inB := make(map[int]struct{}, len(b))
+	for _, l := range b {
+		inB[l] = struct{}{}
+	}
+
+	var res []int
+
+	for _, l := range a {
+		if _, ok := inB[l]; !ok {
+			res = append(res, l)
 		}
 	}
 
@@ -150,3 +352,37 @@ func TotalPercentageDiff(current, base []coverage.Stats) float64 {
 	// round to %.2f
 	return float64(int(math.Round(p*100))) / 100 //nolint:mnd //relax
 }
+
+// NewCodeStats returns coverage statistics of changed lines for each file that has
+// changed lines with statements. Statistics are calculated per line, where line is
+// considered uncovered if it belongs to any uncovered block.
+func NewCodeStats(current []coverage.Stats, changed patch.ChangedLines) []coverage.Stats {
+	var res []coverage.Stats
+
+	for _, s := range current {
+		lines, found := changed.ForFile(s.Name)
+		if !found {
+			continue
+		}
+
+		ns := coverage.Stats{Name: s.Name}
+
+		for _, l := range lines {
+			if slices.Contains(s.UncoveredLines, l) {
+				ns.Total++
+				ns.UncoveredLines = append(ns.UncoveredLines, l)
+			} else if slices.Contains(s.CoveredLines, l) {
+				ns.Total++
+				ns.Covered++
+			}
+		}
+
+		if ns.Total == 0 {
+			continue // changes in this file do not have any statement
+		}
+
+		res = append(res, ns)
+	}
+
+	return res
+}
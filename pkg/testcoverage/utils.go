@@ -2,36 +2,57 @@ package testcoverage
 
 import (
 	"regexp"
+	"strings"
 )
 
 type regRule struct {
-	reg       *regexp.Regexp
-	threshold int
+	reg               *regexp.Regexp
+	threshold         *float64
+	maxUncovered      *int
+	maxUncoveredLines *int
 }
 
-func matches(regexps []regRule, str string) (int, bool) {
+func matches(regexps []regRule, str string) (regRule, bool) {
 	for _, r := range regexps {
 		if r.reg.MatchString(str) {
-			return r.threshold, true
+			return r, true
 		}
 	}
 
-	return 0, false
+	return regRule{}, false
 }
 
 func compileOverridePathRules(cfg Config) []regRule {
-	if len(cfg.Override) == 0 {
-		return nil
-	}
+	return compileOverrideRules(cfg, func(o Override) bool {
+		return !isFunctionOverride(o)
+	})
+}
+
+func compileOverrideFunctionRules(cfg Config) []regRule {
+	return compileOverrideRules(cfg, isFunctionOverride)
+}
 
-	compiled := make([]regRule, len(cfg.Override))
+func compileOverrideRules(cfg Config, predicate func(Override) bool) []regRule {
+	var compiled []regRule
 
-	for i, o := range cfg.Override {
-		compiled[i] = regRule{
-			reg:       regexp.MustCompile(o.Path),
-			threshold: o.Threshold,
+	for _, o := range cfg.Override {
+		if !predicate(o) {
+			continue
 		}
+
+		compiled = append(compiled, regRule{
+			reg:               regexp.MustCompile(o.Path),
+			threshold:         o.Threshold,
+			maxUncovered:      o.MaxUncovered,
+			maxUncoveredLines: o.MaxUncoveredLines,
+		})
 	}
 
 	return compiled
 }
+
+// isFunctionOverride returns true for override rules matching functions,
+// which have path in format `pkg/file.go:Func`.
+func isFunctionOverride(o Override) bool {
+	return strings.Contains(o.Path, ":")
+}